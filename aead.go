@@ -0,0 +1,165 @@
+package goutil
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Algorithm identifies the authenticated encryption algorithm of a ciphertext.
+type Algorithm byte
+
+// Supported authenticated encryption algorithms.
+const (
+	// AESGCM is AES in Galois/Counter Mode. The key size selects AES-128, AES-192 or AES-256.
+	AESGCM Algorithm = iota + 1
+	// ChaCha20Poly1305 is ChaCha20-Poly1305 as specified in RFC 8439, using a 32 bytes key.
+	ChaCha20Poly1305
+)
+
+// envelopeVersion is the version of the ciphertext format produced by Encrypt.
+// A ciphertext is laid out as version (1 byte), algorithm (1 byte), nonce and sealed plaintext.
+const envelopeVersion = 1
+
+var (
+	// ErrUnsupportedAlgorithm is returned for unknown encryption or signature algorithms.
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+	// ErrInvalidCiphertext is returned when a ciphertext is malformed.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	// ErrDecryptionFailed is returned when a ciphertext cannot be authenticated with the key.
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case AESGCM:
+		return "AES-GCM"
+	case ChaCha20Poly1305:
+		return "ChaCha20-Poly1305"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", byte(a))
+	}
+}
+
+// GenerateKey returns a random 32 bytes key, usable with every algorithm.
+// Options select another RandSource, e.g. a seeded one in tests.
+func GenerateKey(opts ...RandOption) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := applyRandOptions(defaultGenerator, opts).Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypt encrypts and authenticates the plaintext with AES-GCM.
+// The additional data is authenticated, but not encrypted; the same data must be passed to Decrypt.
+// The returned ciphertext records the algorithm and a random nonce.
+// Nonces are always read from crypto/rand, since a repeated nonce breaks the encryption.
+func Encrypt(key, plaintext, additionalData []byte) ([]byte, error) {
+	return EncryptWith(AESGCM, key, plaintext, additionalData)
+}
+
+// EncryptWith is like Encrypt, but uses the given algorithm.
+func EncryptWith(alg Algorithm, key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, []byte{envelopeVersion, byte(alg)}, plaintext, additionalData)
+}
+
+// Decrypt authenticates and decrypts a ciphertext produced by Encrypt or EncryptWith.
+// The algorithm is taken from the ciphertext.
+func Decrypt(key, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, ErrInvalidCiphertext
+	}
+	if ciphertext[0] != envelopeVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidCiphertext, ciphertext[0])
+	}
+
+	aead, err := newAEAD(Algorithm(ciphertext[1]), key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext[:2], ciphertext[2:], additionalData)
+}
+
+// EncryptString is like Encrypt, but returns the ciphertext encoded like GenerateToken:
+// URL-safe, Base64 encoded and padded.
+func EncryptString(key []byte, plaintext string, additionalData []byte) (string, error) {
+	ciphertext, err := Encrypt(key, []byte(plaintext), additionalData)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString decrypts a ciphertext produced by EncryptString.
+func DecryptString(key []byte, ciphertext string, additionalData []byte) (string, error) {
+	raw, err := base64.URLEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	plaintext, err := Decrypt(key, raw, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(alg Algorithm, key []byte) (cipher.AEAD, error) {
+	switch alg {
+	case AESGCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		aead, err := chacha20poly1305.New(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		return aead, nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// seal encrypts the plaintext with a random nonce and returns header, nonce and sealed plaintext.
+// The header is authenticated together with the additional data.
+// The nonce deliberately ignores RandOptions and always comes from crypto/rand.
+func seal(aead cipher.AEAD, header, plaintext, additionalData []byte) ([]byte, error) {
+	out := make([]byte, len(header)+aead.NonceSize(), len(header)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	copy(out, header)
+	nonce := out[len(header):]
+	if _, err := defaultGenerator.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, nonce, plaintext, authenticatedData(header, additionalData)), nil
+}
+
+// open reverses seal for the body following the header.
+func open(aead cipher.AEAD, header, body, additionalData []byte) ([]byte, error) {
+	if len(body) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, authenticatedData(header, additionalData))
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+func authenticatedData(header, additionalData []byte) []byte {
+	data := make([]byte, 0, len(header)+len(additionalData))
+	data = append(data, header...)
+	return append(data, additionalData...)
+}
@@ -0,0 +1,201 @@
+package goutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == ':' || unicode.IsSpace(r) }), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		alg       Algorithm
+		key       []byte
+		plaintext []byte
+		aad       []byte
+	}{
+		{"AES-256-GCM", AESGCM, key, []byte("Hello World"), nil},
+		{"AES-128-GCM", AESGCM, key[:16], []byte("Hello World"), []byte("user:42")},
+		{"ChaCha20-Poly1305", ChaCha20Poly1305, key, []byte("Hello World"), []byte("user:42")},
+		{"Empty plaintext", ChaCha20Poly1305, key, []byte{}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ciphertext, err := EncryptWith(test.alg, test.key, test.plaintext, test.aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ciphertext[0] != envelopeVersion || Algorithm(ciphertext[1]) != test.alg {
+				t.Errorf("EncryptWith() header = %v, want version %v and algorithm %v", ciphertext[:2], envelopeVersion, test.alg)
+			}
+
+			got, err := Decrypt(test.key, ciphertext, test.aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, test.plaintext) {
+				t.Errorf("Decrypt() = %s, want %s", got, test.plaintext)
+			}
+
+			again, err := EncryptWith(test.alg, test.key, test.plaintext, test.aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(again, ciphertext) {
+				t.Error("EncryptWith() returned the same ciphertext twice, want random nonces")
+			}
+		})
+	}
+}
+
+func TestGenerateKeyWithRandSource(t *testing.T) {
+	a, err := GenerateKey(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateKey(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 32 || !bytes.Equal(a, b) {
+		t.Errorf("GenerateKey() from the same seed = %x and %x", a, b)
+	}
+
+	ciphertext, err := Encrypt(a, []byte("Hello World"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Encrypt(a, []byte("Hello World"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(ciphertext, again) {
+		t.Error("Encrypt() with a seeded key returned the same ciphertext twice, want random nonces")
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := Encrypt(key, []byte("Hello World"), []byte("aad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modify := func(f func(b []byte)) []byte {
+		b := append([]byte(nil), ciphertext...)
+		f(b)
+		return b
+	}
+
+	tests := []struct {
+		name       string
+		key        []byte
+		ciphertext []byte
+		aad        []byte
+		wantErr    error
+	}{
+		{"Wrong key", otherKey, ciphertext, []byte("aad"), ErrDecryptionFailed},
+		{"Wrong additional data", key, ciphertext, []byte("other"), ErrDecryptionFailed},
+		{"Tampered ciphertext", key, modify(func(b []byte) { b[len(b)-1] ^= 1 }), []byte("aad"), ErrDecryptionFailed},
+		{"Switched algorithm", key, modify(func(b []byte) { b[1] = byte(ChaCha20Poly1305) }), []byte("aad"), ErrDecryptionFailed},
+		{"Unknown algorithm", key, modify(func(b []byte) { b[1] = 99 }), []byte("aad"), ErrUnsupportedAlgorithm},
+		{"Unknown version", key, modify(func(b []byte) { b[0] = 99 }), []byte("aad"), ErrInvalidCiphertext},
+		{"Truncated", key, ciphertext[:10], []byte("aad"), ErrInvalidCiphertext},
+		{"Invalid key size", key[:7], ciphertext, []byte("aad"), ErrInvalidKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Decrypt(test.key, test.ciphertext, test.aad); !errors.Is(err, test.wantErr) {
+				t.Errorf("Decrypt() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+
+	if _, err := EncryptWith(ChaCha20Poly1305, key[:16], []byte("Hello"), nil); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("EncryptWith(ChaCha20Poly1305) with short key error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestEncryptString(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := EncryptString(key, "Hello World", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecryptString(key, ciphertext, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Hello World" {
+		t.Errorf("DecryptString() = %s, want Hello World", got)
+	}
+
+	if _, err := DecryptString(key, "not base64!", nil); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("DecryptString() error = %v, want %v", err, ErrInvalidCiphertext)
+	}
+}
+
+// Test vector from RFC 8439, section 2.8.2.
+func TestChaCha20Poly1305Vector(t *testing.T) {
+	key := decodeHex(t, "808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	nonce := decodeHex(t, "070000004041424344454647")
+	aad := decodeHex(t, "50515253c0c1c2c3c4c5c6c7")
+	plaintext := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	want := decodeHex(t, `d3 1a 8d 34 64 8e 60 db 7b 86 af bc 53 ef 7e c2 a4 ad ed 51 29 6e 08 fe a9 e2 b5 a7 36 ee 62 d6
+		3d be a4 5e 8c a9 67 12 82 fa fb 69 da 92 72 8b 1a 71 de 0a 9e 06 0b 29 05 d6 a5 b6 7e cd 3b 36
+		92 dd bd 7f 2d 77 8b 8c 98 03 ae e3 28 09 1b 58 fa b3 24 e4 fa d6 75 94 55 85 80 8b 48 31 d7 bc
+		3f f4 de f0 8e 4b 7a 9d e5 76 d2 65 86 ce c6 4b 61 16
+		1a e1 0b 59 4f 09 e2 6a 7e 90 2e cb d0 60 06 91`)
+
+	aead, err := newAEAD(ChaCha20Poly1305, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := aead.Seal(nil, nonce, plaintext, aad)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Seal() = %x, want %x", got, want)
+	}
+
+	opened, err := aead.Open(nil, nonce, got, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Open() = %s, want %s", opened, plaintext)
+	}
+
+	got[0] ^= 1
+	if _, err := aead.Open(nil, nonce, got, aad); err == nil {
+		t.Error("Open() of tampered ciphertext error = nil, want error")
+	}
+}
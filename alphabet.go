@@ -0,0 +1,119 @@
+package goutil
+
+import (
+	"errors"
+	"unicode"
+)
+
+// ErrInvalidAlphabet is returned when letters do not form a usable alphabet.
+var ErrInvalidAlphabet = errors.New("alphabet must contain at least two unique letters which either all or none have an upper case form")
+
+// Predefined alphabets for the shift ciphers.
+var (
+	LatinAlphabet    = mustAlphabet("abcdefghijklmnopqrstuvwxyz")
+	GermanAlphabet   = mustAlphabet("abcdefghijklmnopqrstuvwxyzäöü")
+	CyrillicAlphabet = mustAlphabet("абвгдеёжзийклмнопрстуфхцчшщъыьэюя")
+)
+
+// Alphabet is an ordered set of letters the shift ciphers operate on.
+// Letters are matched case-insensitively and keep their case when shifted.
+type Alphabet struct {
+	lower []rune
+	upper []rune
+	index map[rune]letter
+}
+
+// letter is the position of a rune within an alphabet and whether it is the upper case form.
+type letter struct {
+	pos   int
+	upper bool
+}
+
+// NewAlphabet returns an alphabet of the given letters in order.
+// The upper case forms of the letters are derived automatically.
+func NewAlphabet(letters string) (*Alphabet, error) {
+	a := &Alphabet{index: make(map[rune]letter)}
+	var cased int
+	for _, r := range letters {
+		lower := unicode.ToLower(r)
+		if _, ok := a.index[lower]; ok {
+			return nil, ErrInvalidAlphabet
+		}
+		a.index[lower] = letter{pos: len(a.lower)}
+		a.lower = append(a.lower, lower)
+
+		upper := unicode.ToUpper(lower)
+		a.upper = append(a.upper, upper)
+		if upper != lower {
+			cased++
+		}
+	}
+
+	if len(a.lower) < 2 || (cased != 0 && cased != len(a.lower)) {
+		return nil, ErrInvalidAlphabet
+	}
+	if cased == 0 {
+		a.upper = nil
+		return a, nil
+	}
+	for i, upper := range a.upper {
+		if _, ok := a.index[upper]; ok {
+			return nil, ErrInvalidAlphabet
+		}
+		a.index[upper] = letter{pos: i, upper: true}
+	}
+	return a, nil
+}
+
+func mustAlphabet(letters string) *Alphabet {
+	a, err := NewAlphabet(letters)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Len returns the number of letters of the alphabet.
+func (a *Alphabet) Len() int {
+	return len(a.lower)
+}
+
+// Contains reports whether r is a letter of the alphabet in either case.
+func (a *Alphabet) Contains(r rune) bool {
+	_, ok := a.index[r]
+	return ok
+}
+
+// String returns the lower case letters of the alphabet.
+func (a *Alphabet) String() string {
+	return string(a.lower)
+}
+
+// shift moves r by n positions within the alphabet, keeping its case.
+// Runes which are not part of the alphabet are returned unchanged.
+func (a *Alphabet) shift(r rune, n int) rune {
+	l, ok := a.index[r]
+	if !ok {
+		return r
+	}
+	return a.letterAt(l.pos+n, l.upper)
+}
+
+// letterAt returns the letter at position pos, wrapping around in both directions.
+func (a *Alphabet) letterAt(pos int, upper bool) rune {
+	pos = (pos%len(a.lower) + len(a.lower)) % len(a.lower)
+	if upper {
+		return a.upper[pos]
+	}
+	return a.lower[pos]
+}
+
+// shifts returns the position of every character of key.
+// Characters which are not part of the alphabet have a shift of 0.
+func (a *Alphabet) shifts(key string) []int {
+	shifts := make([]int, 0, len(key))
+	for _, r := range key {
+		shifts = append(shifts, a.index[r].pos)
+	}
+	return shifts
+}
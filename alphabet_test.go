@@ -0,0 +1,40 @@
+package goutil
+
+import "testing"
+
+func TestNewAlphabet(t *testing.T) {
+	tests := []struct {
+		name    string
+		letters string
+		wantErr error
+	}{
+		{"Latin", "abcdefghijklmnopqrstuvwxyz", nil},
+		{"Upper case input", "ABC", nil},
+		{"Caseless", "אבגד", nil},
+		{"Duplicate letter", "abca", ErrInvalidAlphabet},
+		{"Duplicate in other case", "abA", ErrInvalidAlphabet},
+		{"Mixed cased and caseless", "abß", ErrInvalidAlphabet},
+		{"Single letter", "a", ErrInvalidAlphabet},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewAlphabet(test.letters)
+			if err != test.wantErr {
+				t.Errorf("NewAlphabet(%s) error = %v, want %v", test.letters, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlphabet(t *testing.T) {
+	if got := GermanAlphabet.Len(); got != 29 {
+		t.Errorf("GermanAlphabet.Len() = %v, want 29", got)
+	}
+	if !GermanAlphabet.Contains('Ä') || GermanAlphabet.Contains('ß') {
+		t.Errorf("GermanAlphabet.Contains() reports wrong letters")
+	}
+	if got := CyrillicAlphabet.String(); got != "абвгдеёжзийклмнопрстуфхцчшщъыьэюя" {
+		t.Errorf("CyrillicAlphabet.String() = %s", got)
+	}
+}
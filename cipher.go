@@ -0,0 +1,302 @@
+package goutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrUnknownCipher is returned when no cipher is registered under a name.
+var ErrUnknownCipher = errors.New("unknown cipher")
+
+// Cipher encodes and decodes text.
+type Cipher interface {
+	Encode(input string) string
+	Decode(input string) string
+	EncodeBytes(input []byte) []byte
+	DecodeBytes(input []byte) []byte
+}
+
+// RuneCipher is a Cipher which transforms text one rune at a time, so it can be applied to streams.
+type RuneCipher interface {
+	Cipher
+	// NewEncoder returns a function encoding the runes of a text in order.
+	NewEncoder() func(rune) rune
+	// NewDecoder returns a function decoding the runes of a text in order.
+	NewDecoder() func(rune) rune
+}
+
+// CipherFactory creates a cipher from a key.
+type CipherFactory func(key string) (Cipher, error)
+
+var cipherRegistry = struct {
+	sync.RWMutex
+	factories map[string]CipherFactory
+}{factories: make(map[string]CipherFactory)}
+
+func init() {
+	RegisterCipher("caesar", func(key string) (Cipher, error) {
+		n, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		return NewCaesar(n, LatinAlphabet), nil
+	})
+	RegisterCipher("vigenere", func(key string) (Cipher, error) {
+		v, err := NewVigenere(key, LatinAlphabet, VigenereEveryRune)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+	RegisterCipher("rot13", func(string) (Cipher, error) {
+		return NewCaesar(13, LatinAlphabet), nil
+	})
+	RegisterCipher("rot47", func(string) (Cipher, error) {
+		return Rot47{}, nil
+	})
+}
+
+// RegisterCipher makes a cipher available by name. Names are case-insensitive.
+// It panics if the name is already registered or the factory is nil.
+func RegisterCipher(name string, factory CipherFactory) {
+	cipherRegistry.Lock()
+	defer cipherRegistry.Unlock()
+
+	name = strings.ToLower(name)
+	if factory == nil {
+		panic("goutil: RegisterCipher factory is nil")
+	}
+	if _, ok := cipherRegistry.factories[name]; ok {
+		panic("goutil: RegisterCipher called twice for cipher " + name)
+	}
+	cipherRegistry.factories[name] = factory
+}
+
+// NewCipher returns the cipher registered under name, set up with the key.
+// The ciphers caesar, vigenere, rot13 and rot47 are always available.
+func NewCipher(name string, key string) (Cipher, error) {
+	cipherRegistry.RLock()
+	factory, ok := cipherRegistry.factories[strings.ToLower(name)]
+	cipherRegistry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCipher, name)
+	}
+	return factory(key)
+}
+
+// Ciphers returns the sorted names of all registered ciphers.
+func Ciphers() []string {
+	cipherRegistry.RLock()
+	defer cipherRegistry.RUnlock()
+
+	names := make([]string, 0, len(cipherRegistry.factories))
+	for name := range cipherRegistry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Caesar is a Caesar cipher over an alphabet.
+type Caesar struct {
+	key      int
+	alphabet *Alphabet
+}
+
+// NewCaesar returns a Caesar cipher shifting the letters of the alphabet by key.
+// If alphabet is nil, the basic alphabet is used.
+func NewCaesar(key int, alphabet *Alphabet) *Caesar {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
+	}
+	return &Caesar{key: key, alphabet: alphabet}
+}
+
+// Encode encodes the input string.
+func (c *Caesar) Encode(input string) string {
+	return CaeserEncodeWith(input, c.key, c.alphabet)
+}
+
+// Decode decodes the input string.
+func (c *Caesar) Decode(input string) string {
+	return CaeserDecodeWith(input, c.key, c.alphabet)
+}
+
+// EncodeBytes encodes the UTF-8 encoded input.
+func (c *Caesar) EncodeBytes(input []byte) []byte {
+	return mapRuneBytes(input, c.NewEncoder())
+}
+
+// DecodeBytes decodes the UTF-8 encoded input.
+func (c *Caesar) DecodeBytes(input []byte) []byte {
+	return mapRuneBytes(input, c.NewDecoder())
+}
+
+// NewEncoder returns a function encoding the runes of a text in order.
+func (c *Caesar) NewEncoder() func(rune) rune {
+	return func(r rune) rune {
+		return c.alphabet.shift(r, c.key)
+	}
+}
+
+// NewDecoder returns a function decoding the runes of a text in order.
+func (c *Caesar) NewDecoder() func(rune) rune {
+	return func(r rune) rune {
+		return c.alphabet.shift(r, -c.key)
+	}
+}
+
+// Rot47 is the ROT47 cipher, which rotates all printable ASCII characters.
+type Rot47 struct{}
+
+// Encode encodes the input string.
+func (Rot47) Encode(input string) string {
+	return Rot47Encode(input)
+}
+
+// Decode decodes the input string.
+func (Rot47) Decode(input string) string {
+	return Rot47Decode(input)
+}
+
+// EncodeBytes encodes the UTF-8 encoded input.
+func (r Rot47) EncodeBytes(input []byte) []byte {
+	return mapRuneBytes(input, r.NewEncoder())
+}
+
+// DecodeBytes decodes the UTF-8 encoded input.
+func (r Rot47) DecodeBytes(input []byte) []byte {
+	return mapRuneBytes(input, r.NewDecoder())
+}
+
+// NewEncoder returns a function encoding the runes of a text in order.
+func (Rot47) NewEncoder() func(rune) rune {
+	return func(r rune) rune {
+		return rot47(r, 47)
+	}
+}
+
+// NewDecoder returns a function decoding the runes of a text in order.
+func (Rot47) NewDecoder() func(rune) rune {
+	return func(r rune) rune {
+		return rot47(r, -47)
+	}
+}
+
+// NewEncodingReader returns a reader encoding everything read from r with the cipher.
+func NewEncodingReader(r io.Reader, c RuneCipher) io.Reader {
+	return &cipherReader{r: r, stream: runeStream{mapping: c.NewEncoder()}}
+}
+
+// NewDecodingReader returns a reader decoding everything read from r with the cipher.
+func NewDecodingReader(r io.Reader, c RuneCipher) io.Reader {
+	return &cipherReader{r: r, stream: runeStream{mapping: c.NewDecoder()}}
+}
+
+// NewEncodingWriter returns a writer encoding everything written to it with the cipher before passing it to w.
+// Close must be called to flush an incomplete UTF-8 sequence at the end; it does not close w.
+func NewEncodingWriter(w io.Writer, c RuneCipher) io.WriteCloser {
+	return &cipherWriter{w: w, stream: runeStream{mapping: c.NewEncoder()}}
+}
+
+// NewDecodingWriter returns a writer decoding everything written to it with the cipher before passing it to w.
+// Close must be called to flush an incomplete UTF-8 sequence at the end; it does not close w.
+func NewDecodingWriter(w io.Writer, c RuneCipher) io.WriteCloser {
+	return &cipherWriter{w: w, stream: runeStream{mapping: c.NewDecoder()}}
+}
+
+type cipherReader struct {
+	r      io.Reader
+	stream runeStream
+	buf    []byte
+	out    []byte
+	err    error
+}
+
+func (cr *cipherReader) Read(p []byte) (int, error) {
+	for len(cr.out) == 0 {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		if cr.buf == nil {
+			cr.buf = make([]byte, 4096)
+		}
+		n, err := cr.r.Read(cr.buf)
+		cr.err = err
+		cr.out = cr.stream.transform(cr.out[:0], cr.buf[:n], err != nil)
+	}
+
+	n := copy(p, cr.out)
+	cr.out = cr.out[n:]
+	return n, nil
+}
+
+type cipherWriter struct {
+	w      io.Writer
+	stream runeStream
+	out    []byte
+}
+
+func (cw *cipherWriter) Write(p []byte) (int, error) {
+	cw.out = cw.stream.transform(cw.out[:0], p, false)
+	if _, err := cw.w.Write(cw.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (cw *cipherWriter) Close() error {
+	cw.out = cw.stream.transform(cw.out[:0], nil, true)
+	if len(cw.out) == 0 {
+		return nil
+	}
+	_, err := cw.w.Write(cw.out)
+	return err
+}
+
+// runeStream applies a rune mapping to UTF-8 text arriving in chunks.
+type runeStream struct {
+	mapping func(rune) rune
+	pending []byte
+}
+
+// transform appends the mapped runes of p to dst. An incomplete rune at the end of p is kept
+// for the next call, unless final is set. Invalid UTF-8 is copied as is.
+func (s *runeStream) transform(dst, p []byte, final bool) []byte {
+	if len(s.pending) > 0 {
+		p = append(s.pending, p...)
+		s.pending = nil
+	}
+
+	for i := 0; i < len(p); {
+		if !final && !utf8.FullRune(p[i:]) {
+			s.pending = append([]byte(nil), p[i:]...)
+			break
+		}
+		r, size := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, p[i])
+		} else {
+			dst = utf8.AppendRune(dst, s.mapping(r))
+		}
+		i += size
+	}
+	return dst
+}
+
+// mapRuneBytes is like mapRunes for UTF-8 encoded bytes.
+func mapRuneBytes(input []byte, mapping func(rune) rune) []byte {
+	s := runeStream{mapping: mapping}
+	return s.transform(make([]byte, 0, len(input)), input, true)
+}
+
+func identityRune(r rune) rune {
+	return r
+}
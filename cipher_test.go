@@ -0,0 +1,198 @@
+package goutil
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewCipher(t *testing.T) {
+	tests := []struct {
+		name   string
+		cipher string
+		key    string
+		input  string
+		want   string
+	}{
+		{
+			name:   "Caesar",
+			cipher: "caesar",
+			key:    "1",
+			input:  "Hello World",
+			want:   "Ifmmp Xpsme",
+		},
+		{
+			name:   "Vigenere",
+			cipher: "Vigenere",
+			key:    "key",
+			input:  "Hello World!",
+			want:   "Rijvs Gspvh!",
+		},
+		{
+			name:   "Rot13",
+			cipher: "ROT13",
+			input:  "Hello World",
+			want:   "Uryyb Jbeyq",
+		},
+		{
+			name:   "Rot47",
+			cipher: "rot47",
+			input:  "Hello World",
+			want:   "w6==@ (@C=5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := NewCipher(test.cipher, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Encode(test.input); got != test.want {
+				t.Errorf("Encode(%s) = %s, want %s", test.input, got, test.want)
+			}
+			if got := c.Decode(test.want); got != test.input {
+				t.Errorf("Decode(%s) = %s, want %s", test.want, got, test.input)
+			}
+			if got := c.EncodeBytes([]byte(test.input)); string(got) != test.want {
+				t.Errorf("EncodeBytes(%s) = %s, want %s", test.input, got, test.want)
+			}
+			if got := c.DecodeBytes([]byte(test.want)); string(got) != test.input {
+				t.Errorf("DecodeBytes(%s) = %s, want %s", test.want, got, test.input)
+			}
+		})
+	}
+}
+
+func TestNewCipherErrors(t *testing.T) {
+	if _, err := NewCipher("enigma", ""); !errors.Is(err, ErrUnknownCipher) {
+		t.Errorf("NewCipher(enigma) error = %v, want %v", err, ErrUnknownCipher)
+	}
+	if _, err := NewCipher("caesar", "three"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("NewCipher(caesar, three) error = %v, want %v", err, ErrInvalidKey)
+	}
+	for _, key := range []string{"", "k3y"} {
+		if c, err := NewCipher("vigenere", key); c != nil || !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("NewCipher(vigenere, %q) = %v, %v, want nil, %v", key, c, err, ErrInvalidKey)
+		}
+	}
+}
+
+func TestRegisterCipher(t *testing.T) {
+	RegisterCipher("test-atbash", func(string) (Cipher, error) {
+		return NewCaesar(0, nil), nil
+	})
+
+	found := false
+	for _, name := range Ciphers() {
+		if name == "test-atbash" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Ciphers() = %v, want test-atbash included", Ciphers())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterCipher() twice did not panic")
+		}
+	}()
+	RegisterCipher("Test-Atbash", func(string) (Cipher, error) {
+		return nil, nil
+	})
+}
+
+func TestEncodingReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		cipher RuneCipher
+		input  string
+	}{
+		{"Caesar", NewCaesar(3, GermanAlphabet), "Grüße aus Köln, Österreich und der Schweiz!"},
+		{"Vigenere", mustVigenere(t, "schlüssel", GermanAlphabet, VigenereAutokey), "Grüße aus Köln, Österreich und der Schweiz!"},
+		{"Rot47", Rot47{}, "Hello World \xff\xfe ünïcödé"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.cipher.Encode(test.input)
+
+			encoded, err := io.ReadAll(NewEncodingReader(iotest.OneByteReader(strings.NewReader(test.input)), test.cipher))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(encoded) != want {
+				t.Errorf("NewEncodingReader() = %q, want %q", encoded, want)
+			}
+
+			decoded, err := io.ReadAll(NewDecodingReader(iotest.HalfReader(bytes.NewReader(encoded)), test.cipher))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(decoded) != test.input {
+				t.Errorf("NewDecodingReader() = %q, want %q", decoded, test.input)
+			}
+		})
+	}
+}
+
+func TestEncodingWriter(t *testing.T) {
+	c := mustVigenere(t, "ключ", CyrillicAlphabet, VigenereLettersOnly)
+	input := "Привет, мир! Как дела?"
+
+	var encoded bytes.Buffer
+	w := NewEncodingWriter(&encoded, c)
+	for _, b := range []byte(input) {
+		if _, err := w.Write([]byte{b}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := c.Encode(input); encoded.String() != want {
+		t.Errorf("NewEncodingWriter() = %q, want %q", encoded.String(), want)
+	}
+
+	var decoded bytes.Buffer
+	w = NewDecodingWriter(&decoded, c)
+	if _, err := w.Write(encoded.Bytes()[:5]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(encoded.Bytes()[5:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.String() != input {
+		t.Errorf("NewDecodingWriter() = %q, want %q", decoded.String(), input)
+	}
+
+	t.Run("Incomplete rune is flushed on close", func(t *testing.T) {
+		var out bytes.Buffer
+		w := NewEncodingWriter(&out, Rot47{})
+		if _, err := w.Write([]byte("ab\xc3")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != "23\xc3" {
+			t.Errorf("NewEncodingWriter() = %q, want %q", out.String(), "23\xc3")
+		}
+	})
+}
+
+func mustVigenere(t *testing.T, key string, alphabet *Alphabet, mode VigenereMode) *Vigenere {
+	t.Helper()
+	v, err := NewVigenere(key, alphabet, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
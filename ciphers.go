@@ -0,0 +1,376 @@
+package goutil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Atbash encoder:
+// This function mirrors every letter of the basic alphabet, so A becomes Z.
+func AtbashEncode(input string) string {
+	return mapRunes(input, func(r rune) rune {
+		l, ok := LatinAlphabet.index[r]
+		if !ok {
+			return r
+		}
+		return LatinAlphabet.letterAt(LatinAlphabet.Len()-1-l.pos, l.upper)
+	})
+}
+
+// Atbash decoder:
+// Atbash is its own inverse, so this is the same as AtbashEncode.
+func AtbashDecode(input string) string {
+	return AtbashEncode(input)
+}
+
+// Affine encoder:
+// This function maps every letter of the basic alphabet at position x to a*x+b.
+// a must be coprime to 26, otherwise the cipher cannot be decoded.
+func AffineEncode(input string, a, b int) (string, error) {
+	if _, err := affineInverse(a); err != nil {
+		return "", err
+	}
+	return mapRunes(input, func(r rune) rune {
+		l, ok := LatinAlphabet.index[r]
+		if !ok {
+			return r
+		}
+		return LatinAlphabet.letterAt(a*l.pos+b, l.upper)
+	}), nil
+}
+
+// Affine decoder:
+// This function reverses AffineEncode with the same a and b.
+func AffineDecode(input string, a, b int) (string, error) {
+	inverse, err := affineInverse(a)
+	if err != nil {
+		return "", err
+	}
+	return mapRunes(input, func(r rune) rune {
+		l, ok := LatinAlphabet.index[r]
+		if !ok {
+			return r
+		}
+		return LatinAlphabet.letterAt(inverse*(l.pos-b), l.upper)
+	}), nil
+}
+
+// affineInverse returns the multiplicative inverse of a modulo the length of the basic alphabet.
+func affineInverse(a int) (int, error) {
+	n := LatinAlphabet.Len()
+	a = (a%n + n) % n
+	for i := 1; i < n; i++ {
+		if a*i%n == 1 {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %d is not coprime to %d", ErrInvalidKey, a, n)
+}
+
+// Beaufort encoder:
+// This function encodes every letter p of the input as key letter minus p.
+// The key must only contain letters of the basic alphabet and advances on letters only.
+func BeaufortEncode(input string, key string) (string, error) {
+	v, err := NewVigenere(key, LatinAlphabet, VigenereLettersOnly)
+	if err != nil {
+		return "", err
+	}
+
+	var i int
+	return mapRunes(input, func(r rune) rune {
+		l, ok := LatinAlphabet.index[r]
+		if !ok {
+			return r
+		}
+		k := v.shifts[i%len(v.shifts)]
+		i++
+		return LatinAlphabet.letterAt(k-l.pos, l.upper)
+	}), nil
+}
+
+// Beaufort decoder:
+// Beaufort is its own inverse, so this is the same as BeaufortEncode.
+func BeaufortDecode(input string, key string) (string, error) {
+	return BeaufortEncode(input, key)
+}
+
+// Rail fence encoder:
+// This function writes the input in a zigzag over the given number of rails and reads it rail by rail.
+func RailFenceEncode(input string, rails int) (string, error) {
+	runes := []rune(input)
+	order, err := railFenceOrder(len(runes), rails)
+	if err != nil {
+		return "", err
+	}
+
+	output := make([]rune, len(runes))
+	for i, pos := range order {
+		output[i] = runes[pos]
+	}
+	return string(output), nil
+}
+
+// Rail fence decoder:
+// This function reverses RailFenceEncode with the same number of rails.
+func RailFenceDecode(input string, rails int) (string, error) {
+	runes := []rune(input)
+	order, err := railFenceOrder(len(runes), rails)
+	if err != nil {
+		return "", err
+	}
+
+	output := make([]rune, len(runes))
+	for i, pos := range order {
+		output[pos] = runes[i]
+	}
+	return string(output), nil
+}
+
+// railFenceOrder returns the positions of the plain text in the order they are read from the rails.
+func railFenceOrder(length, rails int) ([]int, error) {
+	if rails < 2 {
+		return nil, fmt.Errorf("%w: at least 2 rails are needed", ErrInvalidKey)
+	}
+
+	fence := make([][]int, rails)
+	rail, step := 0, 1
+	for i := 0; i < length; i++ {
+		fence[rail] = append(fence[rail], i)
+		if rail == 0 {
+			step = 1
+		} else if rail == rails-1 {
+			step = -1
+		}
+		rail += step
+	}
+
+	order := make([]int, 0, length)
+	for _, positions := range fence {
+		order = append(order, positions...)
+	}
+	return order, nil
+}
+
+// Columnar transposition encoder:
+// This function writes the input in rows of the key's length and reads the columns in the alphabetical order of the key.
+// Columns of equal key letters are read from left to right. The last row is not padded.
+func ColumnarEncode(input string, key string) (string, error) {
+	runes := []rune(input)
+	order, err := columnarOrder(len(runes), key)
+	if err != nil {
+		return "", err
+	}
+
+	output := make([]rune, len(runes))
+	for i, pos := range order {
+		output[i] = runes[pos]
+	}
+	return string(output), nil
+}
+
+// Columnar transposition decoder:
+// This function reverses ColumnarEncode with the same key.
+func ColumnarDecode(input string, key string) (string, error) {
+	runes := []rune(input)
+	order, err := columnarOrder(len(runes), key)
+	if err != nil {
+		return "", err
+	}
+
+	output := make([]rune, len(runes))
+	for i, pos := range order {
+		output[pos] = runes[i]
+	}
+	return string(output), nil
+}
+
+// columnarOrder returns the positions of the plain text in the order they are read from the columns.
+func columnarOrder(length int, key string) ([]int, error) {
+	k := []rune(strings.ToUpper(key))
+	if len(k) == 0 {
+		return nil, fmt.Errorf("%w: key is empty", ErrInvalidKey)
+	}
+
+	columns := make([]int, len(k))
+	for i := range columns {
+		columns[i] = i
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		return k[columns[i]] < k[columns[j]]
+	})
+
+	order := make([]int, 0, length)
+	for _, column := range columns {
+		for pos := column; pos < length; pos += len(k) {
+			order = append(order, pos)
+		}
+	}
+	return order, nil
+}
+
+// Playfair encoder:
+// This function encodes pairs of letters using a 5x5 square built from the key, where J is merged into I.
+// Everything but letters of the basic alphabet is removed and the output is upper case.
+// Pairs of equal letters are split with an X (or a Q if the letter is X) and an odd length is padded the same way.
+func PlayfairEncode(input string, key string) (string, error) {
+	square, err := newPolybiusSquare(key)
+	if err != nil {
+		return "", err
+	}
+
+	letters := square.letters(input)
+	var pairs []rune
+	for i := 0; i < len(letters); {
+		a := letters[i]
+		b := playfairFiller(a)
+		if i+1 < len(letters) && letters[i+1] != a {
+			b = letters[i+1]
+			i++
+		}
+		i++
+		pairs = append(pairs, a, b)
+	}
+	return string(square.playfair(pairs, 1)), nil
+}
+
+// Playfair decoder:
+// This function decodes the input using a 5x5 square built from the key.
+// The padding letters inserted by PlayfairEncode are not removed.
+func PlayfairDecode(input string, key string) (string, error) {
+	square, err := newPolybiusSquare(key)
+	if err != nil {
+		return "", err
+	}
+
+	letters := square.letters(input)
+	if len(letters)%2 != 0 {
+		return "", errors.New("playfair cipher text must have an even number of letters")
+	}
+	for i := 0; i < len(letters); i += 2 {
+		if letters[i] == letters[i+1] {
+			return "", errors.New("playfair cipher text must not contain pairs of equal letters")
+		}
+	}
+	return string(square.playfair(letters, -1)), nil
+}
+
+func playfairFiller(r rune) rune {
+	if r == 'X' {
+		return 'Q'
+	}
+	return 'X'
+}
+
+// Bifid encoder:
+// This function encodes the input by mixing the coordinates of its letters in a 5x5 square built from the key,
+// where J is merged into I. Everything but letters of the basic alphabet is removed and the output is upper case.
+func BifidEncode(input string, key string) (string, error) {
+	square, err := newPolybiusSquare(key)
+	if err != nil {
+		return "", err
+	}
+
+	letters := square.letters(input)
+	coords := make([]int, 2*len(letters))
+	for i, r := range letters {
+		pos := square.pos[r]
+		coords[i] = pos / 5
+		coords[len(letters)+i] = pos % 5
+	}
+
+	output := make([]rune, len(letters))
+	for i := range output {
+		output[i] = square.grid[coords[2*i]*5+coords[2*i+1]]
+	}
+	return string(output), nil
+}
+
+// Bifid decoder:
+// This function reverses BifidEncode with the same key.
+func BifidDecode(input string, key string) (string, error) {
+	square, err := newPolybiusSquare(key)
+	if err != nil {
+		return "", err
+	}
+
+	letters := square.letters(input)
+	coords := make([]int, 0, 2*len(letters))
+	for _, r := range letters {
+		pos := square.pos[r]
+		coords = append(coords, pos/5, pos%5)
+	}
+
+	output := make([]rune, len(letters))
+	for i := range output {
+		output[i] = square.grid[coords[i]*5+coords[len(letters)+i]]
+	}
+	return string(output), nil
+}
+
+// polybiusSquare is a 5x5 grid of the basic alphabet without J.
+type polybiusSquare struct {
+	grid [25]rune
+	pos  map[rune]int
+}
+
+// newPolybiusSquare fills a square with the letters of the key followed by the remaining letters.
+// The key must only contain letters of the basic alphabet and spaces.
+func newPolybiusSquare(key string) (*polybiusSquare, error) {
+	s := &polybiusSquare{pos: make(map[rune]int, 25)}
+	for _, r := range key + "ABCDEFGHIKLMNOPQRSTUVWXYZ" {
+		if r == ' ' {
+			continue
+		}
+		if !LatinAlphabet.Contains(r) {
+			return nil, fmt.Errorf("%w: %q is not a letter of the alphabet", ErrInvalidKey, r)
+		}
+		r = polybiusLetter(r)
+		if _, ok := s.pos[r]; ok {
+			continue
+		}
+		s.pos[r] = len(s.pos)
+		s.grid[s.pos[r]] = r
+	}
+	return s, nil
+}
+
+// letters returns the upper case letters of the input which are part of the square.
+func (s *polybiusSquare) letters(input string) []rune {
+	var letters []rune
+	for _, r := range input {
+		if LatinAlphabet.Contains(r) {
+			letters = append(letters, polybiusLetter(r))
+		}
+	}
+	return letters
+}
+
+// playfair shifts every pair of letters in the given direction.
+func (s *polybiusSquare) playfair(pairs []rune, direction int) []rune {
+	output := make([]rune, len(pairs))
+	for i := 0; i < len(pairs); i += 2 {
+		a, b := s.pos[pairs[i]], s.pos[pairs[i+1]]
+		rowA, colA, rowB, colB := a/5, a%5, b/5, b%5
+		switch {
+		case rowA == rowB:
+			colA, colB = (colA+direction+5)%5, (colB+direction+5)%5
+		case colA == colB:
+			rowA, rowB = (rowA+direction+5)%5, (rowB+direction+5)%5
+		default:
+			colA, colB = colB, colA
+		}
+		output[i], output[i+1] = s.grid[rowA*5+colA], s.grid[rowB*5+colB]
+	}
+	return output
+}
+
+func polybiusLetter(r rune) rune {
+	r = unicode.ToUpper(r)
+	if r == 'J' {
+		return 'I'
+	}
+	return r
+}
@@ -0,0 +1,325 @@
+package goutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAtbash(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "Atbash Simple",
+			input: "Hello World",
+			want:  "Svool Dliow",
+		},
+		{
+			name:  "Atbash Alphabet",
+			input: "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+			want:  "ZYXWVUTSRQPONMLKJIHGFEDCBA",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := AtbashEncode(test.input)
+			if got != test.want {
+				t.Errorf("AtbashEncode(%s) = %s, want %s", test.input, got, test.want)
+			}
+			if back := AtbashDecode(got); back != test.input {
+				t.Errorf("AtbashDecode(%s) = %s, want %s", got, back, test.input)
+			}
+		})
+	}
+}
+
+func TestAffine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		a, b  int
+		want  string
+	}{
+		{
+			name:  "Affine Simple",
+			input: "AFFINE CIPHER",
+			a:     5,
+			b:     8,
+			want:  "IHHWVC SWFRCP",
+		},
+		{
+			name:  "Affine negative keys",
+			input: "Hello World!",
+			a:     -3,
+			b:     -30,
+			want:  "Bkppg Igxpn!",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := AffineEncode(test.input, test.a, test.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("AffineEncode(%s, %v, %v) = %s, want %s", test.input, test.a, test.b, got, test.want)
+			}
+			back, err := AffineDecode(got, test.a, test.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != test.input {
+				t.Errorf("AffineDecode(%s, %v, %v) = %s, want %s", got, test.a, test.b, back, test.input)
+			}
+		})
+	}
+
+	for _, a := range []int{0, 2, 13, 26} {
+		if _, err := AffineEncode("Hello", a, 1); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("AffineEncode(Hello, %v, 1) error = %v, want %v", a, err, ErrInvalidKey)
+		}
+		if _, err := AffineDecode("Hello", a, 1); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("AffineDecode(Hello, %v, 1) error = %v, want %v", a, err, ErrInvalidKey)
+		}
+	}
+}
+
+func TestBeaufort(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		want  string
+	}{
+		{
+			name:  "Beaufort Simple",
+			input: "DEFENDTHEEASTWALLOFTHECASTLE",
+			key:   "FORTIFICATION",
+			want:  "CKMPVCPVWPIWUJOGIUAPVWRIWUUK",
+		},
+		{
+			name:  "Beaufort keeps case and punctuation",
+			input: "Defend the east!",
+			key:   "fortification",
+			want:  "Ckmpvc pvw piwu!",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := BeaufortEncode(test.input, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("BeaufortEncode(%s, %s) = %s, want %s", test.input, test.key, got, test.want)
+			}
+			back, err := BeaufortDecode(got, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != test.input {
+				t.Errorf("BeaufortDecode(%s, %s) = %s, want %s", got, test.key, back, test.input)
+			}
+		})
+	}
+
+	if _, err := BeaufortEncode("Hello", "k3y"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("BeaufortEncode(Hello, k3y) error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestRailFence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		rails int
+		want  string
+	}{
+		{
+			name:  "Rail fence three rails",
+			input: "WEAREDISCOVEREDFLEEATONCE",
+			rails: 3,
+			want:  "WECRLTEERDSOEEFEAOCAIVDEN",
+		},
+		{
+			name:  "Rail fence more rails than runes",
+			input: "Grüße",
+			rails: 10,
+			want:  "Grüße",
+		},
+		{
+			name:  "Rail fence unicode",
+			input: "Grüße Welt",
+			rails: 2,
+			want:  "GüeWlrß et",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := RailFenceEncode(test.input, test.rails)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("RailFenceEncode(%s, %v) = %s, want %s", test.input, test.rails, got, test.want)
+			}
+			back, err := RailFenceDecode(got, test.rails)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != test.input {
+				t.Errorf("RailFenceDecode(%s, %v) = %s, want %s", got, test.rails, back, test.input)
+			}
+		})
+	}
+
+	if _, err := RailFenceEncode("Hello", 1); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("RailFenceEncode(Hello, 1) error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestColumnar(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		want  string
+	}{
+		{
+			name:  "Columnar Simple",
+			input: "WEAREDISCOVEREDFLEEATONCE",
+			key:   "ZEBRAS",
+			want:  "EVLNACDTESEAROFODEECWIREE",
+		},
+		{
+			name:  "Columnar repeated key letters",
+			input: "Hello World",
+			key:   "abba",
+			want:  "Horloe llWd",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ColumnarEncode(test.input, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("ColumnarEncode(%s, %s) = %q, want %q", test.input, test.key, got, test.want)
+			}
+			back, err := ColumnarDecode(got, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != test.input {
+				t.Errorf("ColumnarDecode(%s, %s) = %s, want %s", got, test.key, back, test.input)
+			}
+		})
+	}
+
+	if _, err := ColumnarEncode("Hello", ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ColumnarEncode(Hello, \"\") error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestPlayfair(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		key       string
+		want      string
+		wantPlain string
+	}{
+		{
+			name:      "Playfair Simple",
+			input:     "Hide the gold in the tree stump",
+			key:       "playfair example",
+			want:      "BMODZBXDNABEKUDMUIXMMOUVIF",
+			wantPlain: "HIDETHEGOLDINTHETREXESTUMP",
+		},
+		{
+			name:      "Playfair odd length and J",
+			input:     "jazz",
+			key:       "",
+			wantPlain: "IAZXZX",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := PlayfairEncode(test.input, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.want != "" && got != test.want {
+				t.Errorf("PlayfairEncode(%s, %s) = %s, want %s", test.input, test.key, got, test.want)
+			}
+			back, err := PlayfairDecode(got, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != test.wantPlain {
+				t.Errorf("PlayfairDecode(%s, %s) = %s, want %s", got, test.key, back, test.wantPlain)
+			}
+		})
+	}
+
+	for _, input := range []string{"ABC", "AABB"} {
+		if _, err := PlayfairDecode(input, "key"); err == nil {
+			t.Errorf("PlayfairDecode(%s, key) error = nil, want error", input)
+		}
+	}
+	if _, err := PlayfairEncode("Hello", "k3y"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("PlayfairEncode(Hello, k3y) error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestBifid(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		key       string
+		want      string
+		wantPlain string
+	}{
+		{
+			name:      "Bifid Simple",
+			input:     "FLEEATONCE",
+			key:       "BGWKZQPNDSIOAXEFCLUMTHYVR",
+			want:      "UAEOLWRINS",
+			wantPlain: "FLEEATONCE",
+		},
+		{
+			name:      "Bifid strips non-letters",
+			input:     "Just a test!",
+			key:       "keyword",
+			wantPlain: "IUSTATEST",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := BifidEncode(test.input, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.want != "" && got != test.want {
+				t.Errorf("BifidEncode(%s, %s) = %s, want %s", test.input, test.key, got, test.want)
+			}
+			back, err := BifidDecode(got, test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back != test.wantPlain {
+				t.Errorf("BifidDecode(%s, %s) = %s, want %s", got, test.key, back, test.wantPlain)
+			}
+		})
+	}
+}
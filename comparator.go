@@ -0,0 +1,82 @@
+package goutil
+
+// Comparator compares two values and returns a negative number if a sorts before b,
+// a positive number if a sorts after b and zero if their order does not matter.
+// Comparators compose, e.g. sorting people by last name, then by age from oldest to youngest:
+//
+//	byAge := By(func(p Person) int { return p.Age }).Descending()
+//	SortFunc(&people, By(func(p Person) string { return p.LastName }).Then(byAge).Less)
+type Comparator[A any] func(a, b A) int
+
+// Compare returns -1 if a is less than b, 1 if a is greater than b and 0 otherwise.
+func Compare[C Comparable](a, b C) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// By returns a comparator ordering values by the key in ascending order.
+func By[A any, K Comparable](key func(A) K) Comparator[A] {
+	return func(a, b A) int {
+		return Compare(key(a), key(b))
+	}
+}
+
+// ThenBy returns a comparator ordering values like c and values c considers equal by the key in ascending order.
+func ThenBy[A any, K Comparable](c Comparator[A], key func(A) K) Comparator[A] {
+	return c.Then(By(key))
+}
+
+// NullsFirst returns a comparator ordering values by the value the key points to in ascending order,
+// with nil keys sorting before all others.
+func NullsFirst[A any, K Comparable](key func(A) *K) Comparator[A] {
+	return nullable(key, -1)
+}
+
+// NullsLast is like NullsFirst, but nil keys sort after all others.
+func NullsLast[A any, K Comparable](key func(A) *K) Comparator[A] {
+	return nullable(key, 1)
+}
+
+func nullable[A any, K Comparable](key func(A) *K, null int) Comparator[A] {
+	return func(a, b A) int {
+		ka, kb := key(a), key(b)
+		switch {
+		case ka == nil && kb == nil:
+			return 0
+		case ka == nil:
+			return null
+		case kb == nil:
+			return -null
+		default:
+			return Compare(*ka, *kb)
+		}
+	}
+}
+
+// Then returns a comparator ordering values like c and values c considers equal like next.
+func (c Comparator[A]) Then(next Comparator[A]) Comparator[A] {
+	return func(a, b A) int {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+		return next(a, b)
+	}
+}
+
+// Descending returns a comparator ordering values in the reverse order of c.
+func (c Comparator[A]) Descending() Comparator[A] {
+	return func(a, b A) int {
+		return c(b, a)
+	}
+}
+
+// Less reports whether a sorts before b. It can be passed to SortFunc.
+func (c Comparator[A]) Less(a, b A) bool {
+	return c(a, b) < 0
+}
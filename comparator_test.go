@@ -0,0 +1,74 @@
+package goutil
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testRecord struct {
+	name  string
+	age   int
+	score *float64
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"a", "b", -1},
+		{"b", "a", 1},
+		{"a", "a", 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestComparators(t *testing.T) {
+	records := []testRecord{
+		{"carol", 35, floatPtr(2)},
+		{"alice", 30, nil},
+		{"bob", 25, floatPtr(3)},
+		{"alice", 25, floatPtr(1)},
+		{"bob", 40, nil},
+	}
+	byName := By(func(r testRecord) string { return r.name })
+	byAge := By(func(r testRecord) int { return r.age })
+	score := func(r testRecord) *float64 { return r.score }
+
+	tests := []struct {
+		name string
+		cmp  Comparator[testRecord]
+		want []string
+	}{
+		{"by name then age", ThenBy(byName, func(r testRecord) int { return r.age }), []string{"alice 25", "alice 30", "bob 25", "bob 40", "carol 35"}},
+		{"by name then age descending", byName.Then(byAge.Descending()), []string{"alice 30", "alice 25", "bob 40", "bob 25", "carol 35"}},
+		{"descending", ThenBy(byName, func(r testRecord) int { return r.age }).Descending(), []string{"carol 35", "bob 40", "bob 25", "alice 30", "alice 25"}},
+		{"nulls first", NullsFirst(score).Then(byAge), []string{"alice 30", "bob 40", "alice 25", "carol 35", "bob 25"}},
+		{"nulls last", NullsLast(score).Then(byAge), []string{"alice 25", "carol 35", "bob 25", "alice 30", "bob 40"}},
+		{"by age then nulls first", byAge.Then(NullsFirst(score)), []string{"alice 25", "bob 25", "alice 30", "carol 35", "bob 40"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := append([]testRecord(nil), records...)
+			if err := SortFunc(&got, tt.cmp.Less); err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, r := range got {
+				names = append(names, fmt.Sprintf("%s %d", r.name, r.age))
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("got %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
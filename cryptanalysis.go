@@ -0,0 +1,314 @@
+package goutil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrNotEnoughText is returned when a cipher text has too few letters to be analyzed.
+	ErrNotEnoughText = errors.New("not enough text to analyze")
+	// ErrInvalidLanguage is returned when a language has no alphabet or unusable frequencies.
+	ErrInvalidLanguage = errors.New("invalid language")
+)
+
+// defaultMaxKeyLength is the longest Vigenere key tried if no maximum is given.
+const defaultMaxKeyLength = 20
+
+// Language holds the letter frequencies of a language for frequency analysis.
+type Language struct {
+	Name string
+	// Alphabet is the alphabet of the language.
+	Alphabet *Alphabet
+	// Frequencies holds the relative frequency of every letter of the alphabet, in order.
+	// The values do not need to add up to 1.
+	Frequencies []float64
+}
+
+// Predefined languages for frequency analysis.
+var (
+	LanguageEnglish = &Language{
+		Name:     "English",
+		Alphabet: LatinAlphabet,
+		Frequencies: []float64{
+			8.167, 1.492, 2.782, 4.253, 12.702, 2.228, 2.015, 6.094, 6.966, 0.153, 0.772, 4.025, 2.406,
+			6.749, 7.507, 1.929, 0.095, 5.987, 6.327, 9.056, 2.758, 0.978, 2.360, 0.150, 1.974, 0.074,
+		},
+	}
+	LanguageGerman = &Language{
+		Name:     "German",
+		Alphabet: GermanAlphabet,
+		Frequencies: []float64{
+			6.516, 1.886, 2.732, 5.076, 16.396, 1.656, 3.009, 4.577, 6.550, 0.268, 1.417, 3.437, 2.534,
+			9.776, 2.594, 0.670, 0.018, 7.003, 7.270, 6.154, 4.166, 0.846, 1.921, 0.034, 0.039, 1.134,
+			0.578, 0.443, 0.995,
+		},
+	}
+)
+
+// CaesarCandidate is a possible key of a Caesar cipher text.
+type CaesarCandidate struct {
+	Key int
+	// Score is the chi-squared distance to the language's letter frequencies; lower is better.
+	Score     float64
+	Plaintext string
+}
+
+// VigenereSolution is the recovered key of a Vigenere cipher text.
+type VigenereSolution struct {
+	Key string
+	// Score is the chi-squared distance to the language's letter frequencies; lower is better.
+	Score     float64
+	Plaintext string
+}
+
+// CrackCaesar returns every possible key of the Caesar cipher text, ranked by how well the decoded
+// text matches the letter frequencies of the language. If lang is nil, English is assumed.
+// It returns ErrNotEnoughText if the text contains no letters of the alphabet.
+func CrackCaesar(ciphertext string, lang *Language) ([]CaesarCandidate, error) {
+	lang, err := languageOrDefault(lang)
+	if err != nil {
+		return nil, err
+	}
+	letters := lang.letters(ciphertext)
+	if len(letters) == 0 {
+		return nil, ErrNotEnoughText
+	}
+	counts := lang.count(letters)
+
+	candidates := make([]CaesarCandidate, lang.Alphabet.Len())
+	for key := range candidates {
+		candidates[key] = CaesarCandidate{
+			Key:       key,
+			Score:     lang.chiSquared(counts, key),
+			Plaintext: CaeserDecodeWith(ciphertext, key, lang.Alphabet),
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score < candidates[j].Score
+	})
+	return candidates, nil
+}
+
+// CrackVigenere recovers the key of a Vigenere cipher text whose key advances on letters only.
+// The key length is estimated with the Kasiski examination and the index of coincidence,
+// trying lengths up to maxKeyLength (20 if not positive). If lang is nil, English is assumed.
+func CrackVigenere(ciphertext string, lang *Language, maxKeyLength int) (VigenereSolution, error) {
+	lang, err := languageOrDefault(lang)
+	if err != nil {
+		return VigenereSolution{}, err
+	}
+	letters := lang.letters(ciphertext)
+	if maxKeyLength <= 0 {
+		maxKeyLength = defaultMaxKeyLength
+	}
+	if maxKeyLength > len(letters)/2 {
+		maxKeyLength = len(letters) / 2
+	}
+	if maxKeyLength < 1 {
+		return VigenereSolution{}, ErrNotEnoughText
+	}
+
+	length := lang.estimateKeyLength(letters, maxKeyLength)
+	key := make([]rune, length)
+	var score float64
+	for i := range key {
+		coset := lang.count(cosetOf(letters, i, length))
+		best := CaesarCandidate{Score: -1}
+		for shift := 0; shift < lang.Alphabet.Len(); shift++ {
+			if chi := lang.chiSquared(coset, shift); best.Score < 0 || chi < best.Score {
+				best = CaesarCandidate{Key: shift, Score: chi}
+			}
+		}
+		key[i] = lang.Alphabet.lower[best.Key]
+		score += best.Score
+	}
+	key = shortestPeriod(key)
+
+	v, err := NewVigenere(string(key), lang.Alphabet, VigenereLettersOnly)
+	if err != nil {
+		return VigenereSolution{}, err
+	}
+	return VigenereSolution{
+		Key:       string(key),
+		Score:     score,
+		Plaintext: v.Decode(ciphertext),
+	}, nil
+}
+
+// IndexOfCoincidence returns the probability that two random letters of the text are equal.
+// Only letters of the alphabet are taken into account. If alphabet is nil, the basic alphabet is used.
+func IndexOfCoincidence(text string, alphabet *Alphabet) float64 {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
+	}
+	lang := &Language{Alphabet: alphabet}
+	return indexOfCoincidence(lang.count(lang.letters(text)))
+}
+
+// IndexOfCoincidence returns the probability that two random letters of the language are equal.
+func (l *Language) IndexOfCoincidence() float64 {
+	var total, sum float64
+	for _, f := range l.Frequencies {
+		total += f
+	}
+	for _, f := range l.Frequencies {
+		sum += (f / total) * (f / total)
+	}
+	return sum
+}
+
+// languageOrDefault returns English if lang is nil and otherwise checks that lang can be used for analysis.
+func languageOrDefault(lang *Language) (*Language, error) {
+	if lang == nil {
+		return LanguageEnglish, nil
+	}
+	if lang.Alphabet == nil {
+		return nil, fmt.Errorf("%w: no alphabet", ErrInvalidLanguage)
+	}
+	if len(lang.Frequencies) != lang.Alphabet.Len() {
+		return nil, fmt.Errorf("%w: %d frequencies for %d letters", ErrInvalidLanguage, len(lang.Frequencies), lang.Alphabet.Len())
+	}
+	var sum float64
+	for _, f := range lang.Frequencies {
+		if !(f >= 0) {
+			return nil, fmt.Errorf("%w: negative frequency", ErrInvalidLanguage)
+		}
+		sum += f
+	}
+	if sum == 0 {
+		return nil, fmt.Errorf("%w: all frequencies are zero", ErrInvalidLanguage)
+	}
+	return lang, nil
+}
+
+// letters returns the alphabet positions of all letters of the text.
+func (l *Language) letters(text string) []int {
+	var letters []int
+	for _, r := range text {
+		if letter, ok := l.Alphabet.index[r]; ok {
+			letters = append(letters, letter.pos)
+		}
+	}
+	return letters
+}
+
+// count returns how often every letter of the alphabet occurs.
+func (l *Language) count(letters []int) []int {
+	counts := make([]int, l.Alphabet.Len())
+	for _, pos := range letters {
+		counts[pos]++
+	}
+	return counts
+}
+
+// chiSquared returns the chi-squared distance between the letter counts decoded with shift
+// and the expected frequencies of the language.
+func (l *Language) chiSquared(counts []int, shift int) float64 {
+	var total, sum float64
+	for _, c := range counts {
+		total += float64(c)
+	}
+	for _, f := range l.Frequencies {
+		sum += f
+	}
+
+	var chi float64
+	n := len(counts)
+	for i, f := range l.Frequencies {
+		if f <= 0 {
+			continue
+		}
+		expected := total * f / sum
+		observed := float64(counts[(i+shift)%n])
+		chi += (observed - expected) * (observed - expected) / expected
+	}
+	return chi
+}
+
+// estimateKeyLength returns the most likely key length of a Vigenere cipher text.
+// Each length is rated by its index of coincidence relative to the language, weighted by the share
+// of repeated trigram distances it divides (Kasiski examination). Longer keys are only preferred
+// if they are rated clearly better, as multiples of the key length score as well.
+func (l *Language) estimateKeyLength(letters []int, maxKeyLength int) int {
+	random := 1 / float64(l.Alphabet.Len())
+	expected := l.IndexOfCoincidence()
+	distances := kasiskiDistances(letters)
+
+	best, bestScore := 1, -1.0
+	for length := 1; length <= maxKeyLength; length++ {
+		var ioc float64
+		for i := 0; i < length; i++ {
+			ioc += indexOfCoincidence(l.count(cosetOf(letters, i, length)))
+		}
+		score := (ioc/float64(length) - random) / (expected - random)
+
+		if len(distances) > 0 && length > 1 {
+			var divisible int
+			for _, d := range distances {
+				if d%length == 0 {
+					divisible++
+				}
+			}
+			score *= float64(divisible) / float64(len(distances))
+		}
+
+		if score > bestScore*1.1 {
+			best, bestScore = length, score
+		}
+	}
+	return best
+}
+
+// kasiskiDistances returns the distances between repeated trigrams.
+func kasiskiDistances(letters []int) []int {
+	var distances []int
+	seen := make(map[[3]int]int)
+	for i := 0; i+3 <= len(letters); i++ {
+		trigram := [3]int{letters[i], letters[i+1], letters[i+2]}
+		if last, ok := seen[trigram]; ok {
+			distances = append(distances, i-last)
+		}
+		seen[trigram] = i
+	}
+	return distances
+}
+
+// cosetOf returns every length-th letter starting at offset.
+func cosetOf(letters []int, offset, length int) []int {
+	coset := make([]int, 0, len(letters)/length+1)
+	for i := offset; i < len(letters); i += length {
+		coset = append(coset, letters[i])
+	}
+	return coset
+}
+
+func indexOfCoincidence(counts []int) float64 {
+	var total, sum int
+	for _, c := range counts {
+		total += c
+		sum += c * (c - 1)
+	}
+	if total < 2 {
+		return 0
+	}
+	return float64(sum) / float64(total*(total-1))
+}
+
+// shortestPeriod returns the shortest prefix of key which repeated yields key.
+func shortestPeriod(key []rune) []rune {
+	for period := 1; period < len(key); period++ {
+		if len(key)%period != 0 {
+			continue
+		}
+		repeats := true
+		for i := period; i < len(key) && repeats; i++ {
+			repeats = key[i] == key[i-period]
+		}
+		if repeats {
+			return key[:period]
+		}
+	}
+	return key
+}
@@ -0,0 +1,148 @@
+package goutil
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const englishSample = `It was the best of times, it was the worst of times, it was the age of wisdom, it was the age of
+foolishness, it was the epoch of belief, it was the epoch of incredulity, it was the season of Light, it was the
+season of Darkness, it was the spring of hope, it was the winter of despair, we had everything before us, we had
+nothing before us, we were all going direct to Heaven, we were all going direct the other way, in short, the period
+was so far like the present period, that some of its noisiest authorities insisted on its being received, for good
+or for evil, in the superlative degree of comparison only.`
+
+const germanSample = `Es war einmal ein kleines Mädchen, das hatte jedermann lieb, der sie nur ansah, am allerliebsten
+aber ihre Großmutter, die wusste gar nicht, was sie alles dem Kinde geben sollte. Einmal schenkte sie ihm ein
+Käppchen von rotem Samt, und weil ihm das so wohl stand und es nichts anderes mehr tragen wollte, hieß es nur das
+Rotkäppchen. Eines Tages sprach seine Mutter zu ihm: Komm, Rotkäppchen, da hast du ein Stück Kuchen und eine Flasche
+Wein, bring das der Großmutter hinaus; sie ist krank und schwach und wird sich daran laben. Mach dich auf, bevor es
+heiß wird, und wenn du hinauskommst, so geh hübsch sittsam und lauf nicht vom Weg ab, sonst fällst du und zerbrichst
+das Glas, und die Großmutter hat nichts.`
+
+func TestCrackCaesar(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   int
+		lang  *Language
+	}{
+		{"English", englishSample, 7, nil},
+		{"German", germanSample, 20, LanguageGerman},
+		{"Short English", "Meet me at the usual place at ten rather than eight o'clock", 13, LanguageEnglish},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lang, err := languageOrDefault(test.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			alphabet := lang.Alphabet
+			ciphertext := CaeserEncodeWith(test.input, test.key, alphabet)
+			got, err := CrackCaesar(ciphertext, test.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != alphabet.Len() {
+				t.Fatalf("CrackCaesar() returned %v candidates, want %v", len(got), alphabet.Len())
+			}
+			if got[0].Key != test.key {
+				t.Errorf("CrackCaesar() best key = %v, want %v", got[0].Key, test.key)
+			}
+			if got[0].Plaintext != test.input {
+				t.Errorf("CrackCaesar() best plaintext = %s, want %s", got[0].Plaintext, test.input)
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i].Score < got[i-1].Score {
+					t.Fatalf("CrackCaesar() candidates are not ranked by score")
+				}
+			}
+		})
+	}
+}
+
+func TestCrackVigenere(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		lang  *Language
+	}{
+		{"English short key", englishSample, "lemon", nil},
+		{"English long key", englishSample, "cryptanalysis", LanguageEnglish},
+		{"English single letter key", englishSample, "k", LanguageEnglish},
+		{"German", germanSample, "schlüssel", LanguageGerman},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lang, err := languageOrDefault(test.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := NewVigenere(test.key, lang.Alphabet, VigenereLettersOnly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := CrackVigenere(v.Encode(test.input), test.lang, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Key != test.key {
+				t.Errorf("CrackVigenere() key = %s, want %s", got.Key, test.key)
+			}
+			if got.Plaintext != test.input {
+				t.Errorf("CrackVigenere() plaintext = %s, want %s", got.Plaintext, test.input)
+			}
+		})
+	}
+
+	if _, err := CrackVigenere("a", nil, 0); err != ErrNotEnoughText {
+		t.Errorf("CrackVigenere(a) error = %v, want %v", err, ErrNotEnoughText)
+	}
+}
+
+func TestCrackCaesarWithoutLetters(t *testing.T) {
+	for _, ciphertext := range []string{"", "123", "!? 42"} {
+		got, err := CrackCaesar(ciphertext, nil)
+		if err != ErrNotEnoughText || got != nil {
+			t.Errorf("CrackCaesar(%q) = %v, %v, want %v", ciphertext, got, err, ErrNotEnoughText)
+		}
+	}
+}
+
+func TestInvalidLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		lang *Language
+	}{
+		{"no alphabet", &Language{Frequencies: LanguageEnglish.Frequencies}},
+		{"too few frequencies", &Language{Alphabet: LatinAlphabet, Frequencies: []float64{1, 2, 3}}},
+		{"too many frequencies", &Language{Alphabet: LatinAlphabet, Frequencies: LanguageGerman.Frequencies}},
+		{"zero frequencies", &Language{Alphabet: LatinAlphabet, Frequencies: make([]float64, 26)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := CrackCaesar("Khoor", test.lang); !errors.Is(err, ErrInvalidLanguage) {
+				t.Errorf("CrackCaesar() error = %v, want %v", err, ErrInvalidLanguage)
+			}
+			if _, err := CrackVigenere("Khoor Zruog", test.lang, 0); !errors.Is(err, ErrInvalidLanguage) {
+				t.Errorf("CrackVigenere() error = %v, want %v", err, ErrInvalidLanguage)
+			}
+		})
+	}
+}
+
+func TestIndexOfCoincidence(t *testing.T) {
+	if got := IndexOfCoincidence("aaaa", LatinAlphabet); got != 1 {
+		t.Errorf("IndexOfCoincidence(aaaa) = %v, want 1", got)
+	}
+	if got := IndexOfCoincidence("abcd", LatinAlphabet); got != 0 {
+		t.Errorf("IndexOfCoincidence(abcd) = %v, want 0", got)
+	}
+	if got := LanguageEnglish.IndexOfCoincidence(); math.Abs(got-0.0655) > 0.002 {
+		t.Errorf("LanguageEnglish.IndexOfCoincidence() = %v, want about 0.0655", got)
+	}
+}
@@ -3,19 +3,87 @@ package goutil
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"math/big"
-	"regexp"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
+var (
+	// ErrInvalidMax is returned when a random number is requested with an upper bound lower than 1.
+	ErrInvalidMax = errors.New("max must be greater than 0")
+	// ErrInvalidKey is returned when a cipher key is unusable.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// Generator is a cryptographically secure random generator.
+// It reads its entropy from Source, which defaults to crypto/rand.Reader.
+// Injecting a custom Source makes the output deterministic, e.g. in tests.
+type Generator struct {
+	Source io.Reader
+}
+
+// NewGenerator returns a Generator reading its entropy from source.
+// If source is nil, crypto/rand.Reader is used.
+func NewGenerator(source io.Reader) *Generator {
+	return &Generator{Source: source}
+}
+
+var defaultGenerator = NewGenerator(nil)
+
+func (g *Generator) source() io.Reader {
+	if g == nil || g.Source == nil {
+		return rand.Reader
+	}
+	return g.Source
+}
+
+// Random returns a random number between 0 and max (exclusive).
+func (g *Generator) Random(max int64) (int64, error) {
+	if max <= 0 {
+		return 0, ErrInvalidMax
+	}
+	n, err := g.Uint64n(uint64(max))
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// Token returns a random token of the given length.
+// See GenerateToken for the characteristics of the token.
+func (g *Generator) Token(length int) (string, error) {
+	if length <= 0 {
+		length = 16
+	}
+	b := make([]byte, length)
+	if _, err := g.Read(b); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+// Read fills b with random bytes. It only returns fewer bytes than len(b) together with an error.
+func (g *Generator) Read(b []byte) (int, error) {
+	return io.ReadFull(g.source(), b)
+}
+
 // SecureRandom is a cryptographically secure random number generator.
 // The number generated is between 0 and max.
-func SecureRandom(max int64) int64 {
-	nBig, err := rand.Int(rand.Reader, big.NewInt(max))
+// It panics if max is lower than 1 or no entropy is available, see SecureRandomE.
+// Options select another RandSource, e.g. a seeded one in tests.
+func SecureRandom(max int64, opts ...RandOption) int64 {
+	n, err := SecureRandomE(max, opts...)
 	if err != nil {
 		panic(err)
 	}
-	return nBig.Int64()
+	return n
+}
+
+// SecureRandomE is like SecureRandom, but returns an error instead of panicking.
+func SecureRandomE(max int64, opts ...RandOption) (int64, error) {
+	return applyRandOptions(defaultGenerator, opts).Random(max)
 }
 
 // Generate a random token of the given length.
@@ -23,118 +91,229 @@ func SecureRandom(max int64) int64 {
 // URL-safe,
 // Base64 encoded,
 // padded.
-func GenerateToken(length int) string {
-	if length <= 0 {
-		length = 16
-	}
-	b := make([]byte, length)
-	_, err := rand.Read(b)
+// It panics if no entropy is available, see GenerateTokenE.
+// Options select another RandSource, e.g. a seeded one in tests.
+func GenerateToken(length int, opts ...RandOption) string {
+	token, err := GenerateTokenE(length, opts...)
 	if err != nil {
 		panic(err)
 	}
-	return base64.URLEncoding.EncodeToString(b)
+	return token
+}
+
+// GenerateTokenE is like GenerateToken, but returns an error instead of panicking.
+func GenerateTokenE(length int, opts ...RandOption) (string, error) {
+	return applyRandOptions(defaultGenerator, opts).Token(length)
 }
 
 // Caeser encoder:
 // This function encodes the input string using the key.
 // Only letters of the basic alphabet will be edited.
-// The input is the byte slice and the key.
-// The output is the encoded byte slice.
+// The input is the plain string and the key.
+// The output is the encoded string.
 func CaeserEncode(input string, key int) string {
-	var output []rune = make([]rune, len(input))
-	offset := byte(key % 26)
-
-	for i, v := range input {
-		if v == ' ' {
-			output[i] = ' '
-			continue
-		} else if !regexp.MustCompile(`[a-zA-Z]`).MatchString(string(v)) {
-			output[i] = v
-			continue
-		}
-
-		output[i] = rune(byte(v) + offset)
-		if v >= 'A' && v <= 'Z' {
-			if output[i] > 'Z' {
-				output[i] = output[i] - 26
-			}
-		} else if v >= 'a' && v <= 'z' {
-			if output[i] > 'z' {
-				output[i] = output[i] - 26
-			}
-		}
-	}
-	return string(output)
+	return CaeserEncodeWith(input, key, LatinAlphabet)
 }
 
 // Caeser decoder:
-// This function encodes the input string using the key.
+// This function decodes the input string using the key.
 // Only letters of the basic alphabet will be edited.
-// The input is the byte slice and the key.
-// The output is the encoded byte slice.
+// The input is the encoded string and the key.
+// The output is the decoded string.
 func CaeserDecode(input string, key int) string {
-	var output []rune = make([]rune, len(input))
-	offset := byte(key % 26)
-
-	for i, v := range input {
-		if v == ' ' {
-			output[i] = ' '
-			continue
-		} else if !regexp.MustCompile(`[a-zA-Z]`).MatchString(string(v)) {
-			output[i] = v
-			continue
-		}
+	return CaeserDecodeWith(input, key, LatinAlphabet)
+}
 
-		output[i] = rune(byte(v) - offset)
-		if v >= 'A' && v <= 'Z' {
-			if output[i] < 'A' {
-				output[i] = output[i] + 26
-			}
-		} else if v >= 'a' && v <= 'z' {
-			if output[i] < 'a' {
-				output[i] = output[i] + 26
-			}
-		}
+// CaeserEncodeWith is like CaeserEncode, but shifts the letters of the given alphabet.
+// If alphabet is nil, the basic alphabet is used.
+func CaeserEncodeWith(input string, key int, alphabet *Alphabet) string {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
+	}
+	return mapRunes(input, func(r rune) rune {
+		return alphabet.shift(r, key)
+	})
+}
+
+// CaeserDecodeWith is like CaeserDecode, but shifts the letters of the given alphabet.
+// If alphabet is nil, the basic alphabet is used.
+func CaeserDecodeWith(input string, key int, alphabet *Alphabet) string {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
 	}
-	return string(output)
+	return mapRunes(input, func(r rune) rune {
+		return alphabet.shift(r, -key)
+	})
 }
 
 // Vigenere encoder:
 // This function encodes the input string using the key.
 // Only letters of the basic alphabet will be edited.
-// The input is the byte slice and the key.
-// The output is the encoded byte slice.
+// The input is the plain string and the key.
+// The output is the encoded string.
 func VigenereEncode(input string, key string) string {
-	var output []rune = make([]rune, len(input))
-	var offset byte
-
-	for i, v := range input {
-		key = strings.ToLower(key)
-		offset = key[i%len(key)] - 'a'
-
-		caeser := CaeserEncode(string(v), int(offset))
-		output[i] = rune(caeser[0])
-	}
-	return string(output)
+	return VigenereEncodeWith(input, key, LatinAlphabet)
 }
 
 // Vigenere decoder:
 // This function decodes the input string using the key.
 // Only letters of the basic alphabet will be edited.
-// The input is the ciphered byte slice and the key.
-// The output is the deciphered byte slice.
+// The input is the encoded string and the key.
+// The output is the decoded string.
 func VigenereDecode(input string, key string) string {
-	var output []rune = make([]rune, len(input))
-	var offset byte
+	return VigenereDecodeWith(input, key, LatinAlphabet)
+}
+
+// VigenereEncodeWith is like VigenereEncode, but shifts the letters of the given alphabet.
+// Key characters which are not part of the alphabet do not shift. If alphabet is nil, the basic alphabet is used.
+func VigenereEncodeWith(input string, key string, alphabet *Alphabet) string {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
+	}
+	v := &Vigenere{alphabet: alphabet, shifts: alphabet.shifts(key)}
+	return v.Encode(input)
+}
 
-	for i, v := range input {
-		key = strings.ToLower(key)
-		offset = key[i%len(key)] - 'a'
+// VigenereDecodeWith is like VigenereDecode, but shifts the letters of the given alphabet.
+// Key characters which are not part of the alphabet do not shift. If alphabet is nil, the basic alphabet is used.
+func VigenereDecodeWith(input string, key string, alphabet *Alphabet) string {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
+	}
+	v := &Vigenere{alphabet: alphabet, shifts: alphabet.shifts(key)}
+	return v.Decode(input)
+}
+
+// Autokey encoder:
+// This function encodes the input string using the autokey variant of the Vigenere cipher.
+// The key must only contain letters of the basic alphabet.
+func AutokeyEncode(input string, key string) (string, error) {
+	v, err := NewVigenere(key, LatinAlphabet, VigenereAutokey)
+	if err != nil {
+		return "", err
+	}
+	return v.Encode(input), nil
+}
+
+// Autokey decoder:
+// This function decodes the input string using the autokey variant of the Vigenere cipher.
+// The key must only contain letters of the basic alphabet.
+func AutokeyDecode(input string, key string) (string, error) {
+	v, err := NewVigenere(key, LatinAlphabet, VigenereAutokey)
+	if err != nil {
+		return "", err
+	}
+	return v.Decode(input), nil
+}
+
+// VigenereMode selects how the key of a Vigenere cipher advances.
+type VigenereMode int
+
+const (
+	// VigenereEveryRune advances the key on every rune, like VigenereEncode.
+	VigenereEveryRune VigenereMode = iota
+	// VigenereLettersOnly advances the key on letters of the alphabet only, which is the classical behaviour.
+	VigenereLettersOnly
+	// VigenereAutokey extends the key with the plain text instead of repeating it.
+	// The key advances on letters of the alphabet only.
+	VigenereAutokey
+)
 
-		caeser := CaeserDecode(string(v), int(offset))
-		output[i] = rune(caeser[0])
+// Vigenere is a Vigenere cipher with a validated key.
+type Vigenere struct {
+	alphabet *Alphabet
+	shifts   []int
+	mode     VigenereMode
+}
+
+// NewVigenere returns a Vigenere cipher using the key and alphabet.
+// The key must not be empty and only contain letters of the alphabet.
+// If alphabet is nil, the basic alphabet is used.
+func NewVigenere(key string, alphabet *Alphabet, mode VigenereMode) (*Vigenere, error) {
+	if alphabet == nil {
+		alphabet = LatinAlphabet
 	}
-	return string(output)
+	if key == "" {
+		return nil, fmt.Errorf("%w: key is empty", ErrInvalidKey)
+	}
+	for _, r := range key {
+		if !alphabet.Contains(r) {
+			return nil, fmt.Errorf("%w: %q is not a letter of the alphabet", ErrInvalidKey, r)
+		}
+	}
+	if mode < VigenereEveryRune || mode > VigenereAutokey {
+		return nil, fmt.Errorf("unknown vigenere mode %d", mode)
+	}
+	return &Vigenere{alphabet: alphabet, shifts: alphabet.shifts(key), mode: mode}, nil
+}
+
+// Encode encodes the input string.
+func (v *Vigenere) Encode(input string) string {
+	return mapRunes(input, v.NewEncoder())
+}
+
+// Decode decodes the input string.
+func (v *Vigenere) Decode(input string) string {
+	return mapRunes(input, v.NewDecoder())
+}
+
+// EncodeBytes encodes the UTF-8 encoded input.
+func (v *Vigenere) EncodeBytes(input []byte) []byte {
+	return mapRuneBytes(input, v.NewEncoder())
+}
+
+// DecodeBytes decodes the UTF-8 encoded input.
+func (v *Vigenere) DecodeBytes(input []byte) []byte {
+	return mapRuneBytes(input, v.NewDecoder())
+}
+
+// NewEncoder returns a function encoding the runes of a text in order.
+func (v *Vigenere) NewEncoder() func(rune) rune {
+	if len(v.shifts) == 0 {
+		return identityRune
+	}
+	return v.stream(1).next
+}
+
+// NewDecoder returns a function decoding the runes of a text in order.
+func (v *Vigenere) NewDecoder() func(rune) rune {
+	if len(v.shifts) == 0 {
+		return identityRune
+	}
+	return v.stream(-1).next
+}
+
+func (v *Vigenere) stream(direction int) *vigenereStream {
+	key := make([]int, len(v.shifts))
+	copy(key, v.shifts)
+	return &vigenereStream{v: v, direction: direction, key: key}
+}
+
+// vigenereStream holds the position within the key while encoding or decoding.
+type vigenereStream struct {
+	v         *Vigenere
+	direction int
+	key       []int
+	pos       int
+}
+
+// next shifts r by the current key letter and advances the key.
+func (s *vigenereStream) next(r rune) rune {
+	if s.v.mode != VigenereEveryRune && !s.v.alphabet.Contains(r) {
+		return r
+	}
+
+	out := s.v.alphabet.shift(r, s.direction*s.key[s.pos])
+	if s.v.mode == VigenereAutokey {
+		// The key letter used len(key) letters from now is the current plain text letter.
+		plain := r
+		if s.direction < 0 {
+			plain = out
+		}
+		s.key[s.pos] = s.v.alphabet.index[plain].pos
+	}
+	s.pos = (s.pos + 1) % len(s.key)
+	return out
 }
 
 // Rot13 cipher encoder:
@@ -148,38 +327,39 @@ func Rot13Decode(input string) string {
 
 // Rot47 cipher encoder
 func Rot47Encode(input string) string {
-	output := make([]rune, len(input))
-	offset := 47
-
-	for i, v := range input {
-		asciiCode := int(v)
-		if 33 <= asciiCode && asciiCode <= 126 {
-			output[i] = v + rune(offset)
-			if output[i] > 126 {
-				output[i] = output[i] - 94
-			}
-		} else {
-			output[i] = v
-		}
-	}
-	return string(output)
+	return mapRunes(input, func(r rune) rune {
+		return rot47(r, 47)
+	})
 }
 
 // Rot47 cipher decoder
 func Rot47Decode(input string) string {
-	output := make([]rune, len(input))
-	offset := 47
-
-	for i, v := range input {
-		asciiCode := int(v)
-		if 33 <= asciiCode && asciiCode <= 126 {
-			output[i] = v - rune(offset)
-			if output[i] < 33 {
-				output[i] = output[i] + 94
-			}
+	return mapRunes(input, func(r rune) rune {
+		return rot47(r, -47)
+	})
+}
+
+// rot47 shifts printable ASCII characters by offset.
+func rot47(r rune, offset rune) rune {
+	if r < 33 || r > 126 {
+		return r
+	}
+	return 33 + ((r-33+offset)%94+94)%94
+}
+
+// mapRunes returns the input with every rune replaced by mapping.
+// Unlike strings.Map, invalid UTF-8 is copied as is instead of being replaced.
+func mapRunes(input string, mapping func(rune) rune) string {
+	var sb strings.Builder
+	sb.Grow(len(input))
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if r == utf8.RuneError && size == 1 {
+			sb.WriteByte(input[i])
 		} else {
-			output[i] = v
+			sb.WriteRune(mapping(r))
 		}
+		i += size
 	}
-	return string(output)
+	return sb.String()
 }
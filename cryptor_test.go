@@ -1,6 +1,8 @@
 package goutil
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -42,6 +44,88 @@ func TestToken(t *testing.T) {
 	}
 }
 
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestSecureRandomE(t *testing.T) {
+	tests := []struct {
+		name    string
+		max     int64
+		wantErr error
+	}{
+		{
+			name: "Valid max",
+			max:  10,
+		},
+		{
+			name:    "Zero max",
+			max:     0,
+			wantErr: ErrInvalidMax,
+		},
+		{
+			name:    "Negative max",
+			max:     -5,
+			wantErr: ErrInvalidMax,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := SecureRandomE(test.max)
+			if err != test.wantErr {
+				t.Fatalf("SecureRandomE(%v) error = %v, want %v", test.max, err, test.wantErr)
+			}
+			if err == nil && (got < 0 || got >= test.max) {
+				t.Errorf("SecureRandomE(%v) = %v, out of range", test.max, got)
+			}
+		})
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	t.Run("Deterministic source", func(t *testing.T) {
+		seed := bytes.Repeat([]byte{0x2a}, 64)
+		a, err := NewGenerator(bytes.NewReader(seed)).Token(16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewGenerator(bytes.NewReader(seed)).Token(16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != b {
+			t.Errorf("Token() = %s and %s, want equal tokens", a, b)
+		}
+	})
+
+	t.Run("Failing source", func(t *testing.T) {
+		g := NewGenerator(failingReader{})
+		if _, err := g.Random(10); err == nil {
+			t.Error("Random() error = nil, want error")
+		}
+		if _, err := g.Token(16); err == nil {
+			t.Error("Token() error = nil, want error")
+		}
+	})
+
+	t.Run("Exhausted source", func(t *testing.T) {
+		g := NewGenerator(bytes.NewReader([]byte{1, 2, 3}))
+		if _, err := g.Token(16); err == nil {
+			t.Error("Token() error = nil, want error")
+		}
+	})
+
+	t.Run("Zero value", func(t *testing.T) {
+		var g Generator
+		if _, err := g.Token(16); err != nil {
+			t.Errorf("Token() error = %v, want nil", err)
+		}
+	})
+}
+
 func TestCaeserEncode(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -116,6 +200,115 @@ func TestCaeserDecode(t *testing.T) {
 	}
 }
 
+func TestCaeserUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		key      int
+		alphabet *Alphabet
+		want     string
+	}{
+		{
+			name:     "Latin alphabet keeps umlauts",
+			input:    "Grüße",
+			key:      1,
+			alphabet: LatinAlphabet,
+			want:     "Hsüßf",
+		},
+		{
+			name:     "German alphabet shifts umlauts",
+			input:    "Grüße",
+			key:      1,
+			alphabet: GermanAlphabet,
+			want:     "Hsaßf",
+		},
+		{
+			name:     "Cyrillic alphabet",
+			input:    "Привет, мир!",
+			key:      1,
+			alphabet: CyrillicAlphabet,
+			want:     "Рсйгёу, нйс!",
+		},
+		{
+			name:     "Nil alphabet is the basic alphabet",
+			input:    "Hello, World!",
+			key:      3,
+			alphabet: nil,
+			want:     "Khoor, Zruog!",
+		},
+		{
+			name:     "Invalid UTF-8 is kept",
+			input:    "ab\xffc",
+			key:      2,
+			alphabet: LatinAlphabet,
+			want:     "cd\xffe",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := CaeserEncodeWith(test.input, test.key, test.alphabet)
+			if got != test.want {
+				t.Errorf("CaeserEncodeWith(%q, %v) = %q, want %q", test.input, test.key, got, test.want)
+			}
+			if back := CaeserDecodeWith(got, test.key, test.alphabet); back != test.input {
+				t.Errorf("CaeserDecodeWith(%q, %v) = %q, want %q", got, test.key, back, test.input)
+			}
+		})
+	}
+}
+
+func TestVigenereUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		key      string
+		alphabet *Alphabet
+		want     string
+	}{
+		{
+			name:     "Latin alphabet keeps umlauts",
+			input:    "Grüße Welt",
+			key:      "b",
+			alphabet: LatinAlphabet,
+			want:     "Hsüßf Xfmu",
+		},
+		{
+			name:     "German key and input",
+			input:    "Öl",
+			key:      "äb",
+			alphabet: GermanAlphabet,
+			want:     "Ym",
+		},
+		{
+			name:     "Nil alphabet is the basic alphabet",
+			input:    "Grüße Welt",
+			key:      "b",
+			alphabet: nil,
+			want:     "Hsüßf Xfmu",
+		},
+		{
+			name:     "Empty key",
+			input:    "Hello",
+			key:      "",
+			alphabet: LatinAlphabet,
+			want:     "Hello",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := VigenereEncodeWith(test.input, test.key, test.alphabet)
+			if got != test.want {
+				t.Errorf("VigenereEncodeWith(%q, %q) = %q, want %q", test.input, test.key, got, test.want)
+			}
+			if back := VigenereDecodeWith(got, test.key, test.alphabet); back != test.input {
+				t.Errorf("VigenereDecodeWith(%q, %q) = %q, want %q", got, test.key, back, test.input)
+			}
+		})
+	}
+}
+
 func TestVigenereEncode(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -195,6 +388,90 @@ func TestVigenereDecode(t *testing.T) {
 		})
 	}
 }
+func TestVigenereModes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		mode  VigenereMode
+		want  string
+	}{
+		{
+			name:  "Every rune",
+			input: "ATTACK AT DAWN",
+			key:   "LEMON",
+			mode:  VigenereEveryRune,
+			want:  "LXFOPV MH OEIB",
+		},
+		{
+			name:  "Letters only",
+			input: "ATTACK AT DAWN",
+			key:   "LEMON",
+			mode:  VigenereLettersOnly,
+			want:  "LXFOPV EF RNHR",
+		},
+		{
+			name:  "Autokey",
+			input: "attack at dawn",
+			key:   "QUEENLY",
+			mode:  VigenereAutokey,
+			want:  "qnxepv yt wtwp",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := NewVigenere(test.key, nil, test.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := v.Encode(test.input)
+			if got != test.want {
+				t.Errorf("Encode(%s) = %s, want %s", test.input, got, test.want)
+			}
+			if back := v.Decode(got); back != test.input {
+				t.Errorf("Decode(%s) = %s, want %s", got, back, test.input)
+			}
+		})
+	}
+}
+
+func TestNewVigenereInvalidKey(t *testing.T) {
+	for _, key := range []string{"", "k3y", "key word", "schlüssel"} {
+		t.Run(key, func(t *testing.T) {
+			if _, err := NewVigenere(key, LatinAlphabet, VigenereLettersOnly); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("NewVigenere(%q) error = %v, want %v", key, err, ErrInvalidKey)
+			}
+		})
+	}
+
+	if _, err := NewVigenere("schlüssel", GermanAlphabet, VigenereLettersOnly); err != nil {
+		t.Errorf("NewVigenere(schlüssel) error = %v, want nil", err)
+	}
+}
+
+func TestAutokey(t *testing.T) {
+	got, err := AutokeyEncode("ATTACKATDAWN", "QUEENLY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "QNXEPVYTWTWP" {
+		t.Errorf("AutokeyEncode(ATTACKATDAWN, QUEENLY) = %s, want QNXEPVYTWTWP", got)
+	}
+
+	back, err := AutokeyDecode(got, "QUEENLY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back != "ATTACKATDAWN" {
+		t.Errorf("AutokeyDecode(%s, QUEENLY) = %s, want ATTACKATDAWN", got, back)
+	}
+
+	if _, err := AutokeyEncode("ATTACK", ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("AutokeyEncode(ATTACK, \"\") error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
 func TestRot13Encode(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -262,6 +539,11 @@ func TestRot47Encode(t *testing.T) {
 			input: "Hello World",
 			want:  "w6==@ (@C=5",
 		},
+		{
+			name:  "Rot47 Unicode",
+			input: "Grüße!",
+			want:  "vCüß6P",
+		},
 		{
 			name:  "Rot47 All",
 			input: "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~",
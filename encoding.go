@@ -0,0 +1,489 @@
+package goutil
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidEncoding is returned when encoded input cannot be decoded.
+// Decoding errors are of type *DecodeError, which unwraps to it.
+var ErrInvalidEncoding = errors.New("invalid encoding")
+
+// Encoding converts binary data to text and back. Decoding is strict: any character outside
+// the alphabet, including whitespace, is rejected with a *DecodeError reporting its offset.
+type Encoding interface {
+	// EncodeToString returns the encoding of src.
+	EncodeToString(src []byte) string
+	// DecodeString returns the bytes represented by s.
+	DecodeString(s string) ([]byte, error)
+	// NewEncoder returns a writer encoding everything written to it before passing it to w.
+	// Close must be called to flush the final partial block; it does not close w.
+	NewEncoder(w io.Writer) io.WriteCloser
+	// NewDecoder returns a reader decoding everything read from r.
+	NewDecoder(r io.Reader) io.Reader
+}
+
+// Encodings for binary data.
+//
+// Base58, Base58Check and Base62 treat the input as one big number, so their encoders and decoders
+// only produce output once the whole input has been written or read.
+var (
+	// HexEncoding is lower case hexadecimal. Upper case is accepted when decoding.
+	HexEncoding Encoding = textEncoding{hexCodec{}}
+	// Base32Encoding is the standard base32 encoding with padding, as defined in RFC 4648.
+	Base32Encoding Encoding = textEncoding{base32Codec{alphabet: "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567", padded: true}}
+	// CrockfordEncoding is Crockford's base32 without padding. Decoding ignores case
+	// and reads I and L as 1 and O as 0.
+	CrockfordEncoding Encoding = textEncoding{base32Codec{alphabet: CharsetCrockford, value: crockfordValue}}
+	// Base58Encoding is base58 with the Bitcoin alphabet. Leading zero bytes are encoded as "1".
+	Base58Encoding Encoding = textEncoding{radixCodec{alphabet: CharsetBase58}}
+	// Base58CheckEncoding is like Base58Encoding, but appends a four bytes double SHA-256 checksum
+	// which is verified when decoding.
+	Base58CheckEncoding Encoding = textEncoding{radixCodec{alphabet: CharsetBase58, checksum: true}}
+	// Base62Encoding is base62 with the alphabet 0-9A-Za-z. Leading zero bytes are encoded as "0".
+	Base62Encoding Encoding = textEncoding{radixCodec{alphabet: CharsetBase62}}
+	// Ascii85Encoding is the btoa and Adobe Ascii85 encoding, without the <~ ~> delimiters.
+	// Four zero bytes are encoded as "z".
+	Ascii85Encoding Encoding = textEncoding{ascii85Codec{}}
+)
+
+// DecodeError reports invalid encoded input.
+type DecodeError struct {
+	// Offset is the byte offset of the invalid input.
+	Offset int64
+	// Reason describes what is invalid.
+	Reason string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%v at offset %d: %s", ErrInvalidEncoding, e.Offset, e.Reason)
+}
+
+// Unwrap returns ErrInvalidEncoding.
+func (e *DecodeError) Unwrap() error {
+	return ErrInvalidEncoding
+}
+
+func invalidChar(offset int, c byte) *DecodeError {
+	return &DecodeError{Offset: int64(offset), Reason: fmt.Sprintf("invalid character %q", c)}
+}
+
+// codec converts chunks of data, so that encodings can be applied to streams.
+type codec interface {
+	// encode appends the encoding of a prefix of src to dst and returns the number of bytes consumed.
+	// Unless final is set, only whole blocks are consumed.
+	encode(dst, src []byte, final bool) ([]byte, int)
+	// decode appends the decoding of a prefix of src to dst and returns the number of bytes consumed.
+	// Unless final is set, only whole blocks are consumed; otherwise all of src is consumed.
+	// Error offsets are relative to src.
+	decode(dst, src []byte, final bool) ([]byte, int, error)
+}
+
+type textEncoding struct {
+	codec codec
+}
+
+func (e textEncoding) EncodeToString(src []byte) string {
+	dst, _ := e.codec.encode(nil, src, true)
+	return string(dst)
+}
+
+func (e textEncoding) DecodeString(s string) ([]byte, error) {
+	dst, _, err := e.codec.decode(nil, []byte(s), true)
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func (e textEncoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return &encodingWriter{w: w, codec: e.codec}
+}
+
+func (e textEncoding) NewDecoder(r io.Reader) io.Reader {
+	return &encodingReader{r: r, codec: e.codec}
+}
+
+type encodingWriter struct {
+	w       io.Writer
+	codec   codec
+	pending []byte
+	out     []byte
+}
+
+func (ew *encodingWriter) Write(p []byte) (int, error) {
+	ew.pending = append(ew.pending, p...)
+	var n int
+	ew.out, n = ew.codec.encode(ew.out[:0], ew.pending, false)
+	ew.pending = append(ew.pending[:0], ew.pending[n:]...)
+	if _, err := ew.w.Write(ew.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (ew *encodingWriter) Close() error {
+	ew.out, _ = ew.codec.encode(ew.out[:0], ew.pending, true)
+	ew.pending = nil
+	if len(ew.out) == 0 {
+		return nil
+	}
+	_, err := ew.w.Write(ew.out)
+	return err
+}
+
+type encodingReader struct {
+	r       io.Reader
+	codec   codec
+	buf     []byte
+	pending []byte
+	offset  int64
+	out     []byte
+	err     error
+}
+
+func (er *encodingReader) Read(p []byte) (int, error) {
+	for len(er.out) == 0 {
+		if er.err != nil {
+			return 0, er.err
+		}
+		if er.buf == nil {
+			er.buf = make([]byte, 4096)
+		}
+		n, err := er.r.Read(er.buf)
+		er.pending = append(er.pending, er.buf[:n]...)
+		er.err = err
+
+		out, consumed, decodeErr := er.codec.decode(er.out[:0], er.pending, err != nil)
+		if decodeErr != nil {
+			var de *DecodeError
+			if errors.As(decodeErr, &de) {
+				de.Offset += er.offset
+			}
+			er.err = decodeErr
+		}
+		er.out = out
+		er.offset += int64(consumed)
+		er.pending = append(er.pending[:0], er.pending[consumed:]...)
+	}
+
+	n := copy(p, er.out)
+	er.out = er.out[n:]
+	return n, nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+type hexCodec struct{}
+
+func (hexCodec) encode(dst, src []byte, final bool) ([]byte, int) {
+	for _, b := range src {
+		dst = append(dst, hexDigits[b>>4], hexDigits[b&0x0f])
+	}
+	return dst, len(src)
+}
+
+func (hexCodec) decode(dst, src []byte, final bool) ([]byte, int, error) {
+	n := len(src) - len(src)%2
+	for i := 0; i < n; i += 2 {
+		hi, lo := hexValue(src[i]), hexValue(src[i+1])
+		if hi < 0 {
+			return dst, i, invalidChar(i, src[i])
+		}
+		if lo < 0 {
+			return dst, i, invalidChar(i+1, src[i+1])
+		}
+		dst = append(dst, byte(hi<<4|lo))
+	}
+	if final && n < len(src) {
+		if hexValue(src[n]) < 0 {
+			return dst, n, invalidChar(n, src[n])
+		}
+		return dst, n, &DecodeError{Offset: int64(n), Reason: "odd length"}
+	}
+	return dst, n, nil
+}
+
+func hexValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c - 'a' + 10)
+	case c >= 'A' && c <= 'F':
+		return int(c - 'A' + 10)
+	}
+	return -1
+}
+
+// base32Codec encodes blocks of 5 bytes as 8 characters.
+type base32Codec struct {
+	alphabet string
+	padded   bool
+	// value returns the value of a character, or -1. Defaults to the index in the alphabet.
+	value func(c byte) int
+}
+
+func (c base32Codec) encode(dst, src []byte, final bool) ([]byte, int) {
+	n := len(src)
+	if !final {
+		n -= n % 5
+	}
+	for i := 0; i < n; i += 5 {
+		block := src[i:]
+		if len(block) > 5 {
+			block = block[:5]
+		}
+		var v uint64
+		for j := 0; j < 5; j++ {
+			v <<= 8
+			if j < len(block) {
+				v |= uint64(block[j])
+			}
+		}
+		chars := (len(block)*8 + 4) / 5
+		for j := 0; j < chars; j++ {
+			dst = append(dst, c.alphabet[v>>(35-5*j)&0x1f])
+		}
+		if c.padded {
+			for j := chars; j < 8; j++ {
+				dst = append(dst, '=')
+			}
+		}
+	}
+	return dst, n
+}
+
+func (c base32Codec) decode(dst, src []byte, final bool) ([]byte, int, error) {
+	i := 0
+	for i < len(src) {
+		group := src[i:]
+		if len(group) > 8 {
+			group = group[:8]
+		}
+		// A padded group may only be the last one, so wait for the end of the input.
+		if !final && (len(group) < 8 || c.padded && group[7] == '=') {
+			break
+		}
+		if c.padded && len(group) < 8 {
+			return dst, i, &DecodeError{Offset: int64(i), Reason: "incomplete group"}
+		}
+
+		var v uint64
+		n := 0
+		for ; n < len(group); n++ {
+			if c.padded && group[n] == '=' {
+				break
+			}
+			d := c.charValue(group[n])
+			if d < 0 {
+				return dst, i, invalidChar(i+n, group[n])
+			}
+			v = v<<5 | uint64(d)
+		}
+		for j := n; j < len(group); j++ {
+			if group[j] != '=' {
+				return dst, i, invalidChar(i+j, group[j])
+			}
+		}
+		if n < 8 && i+len(group) < len(src) {
+			return dst, i, &DecodeError{Offset: int64(i + len(group)), Reason: "data after padding"}
+		}
+		if n != 2 && n != 4 && n != 5 && n != 7 && n != 8 {
+			return dst, i, &DecodeError{Offset: int64(i + n), Reason: "incomplete group"}
+		}
+
+		size := n * 5 / 8
+		extra := uint(n*5 - size*8)
+		if v&(1<<extra-1) != 0 {
+			return dst, i, &DecodeError{Offset: int64(i + n - 1), Reason: "non-zero trailing bits"}
+		}
+		v >>= extra
+		for j := size - 1; j >= 0; j-- {
+			dst = append(dst, byte(v>>(8*j)))
+		}
+		i += len(group)
+	}
+	return dst, i, nil
+}
+
+func (c base32Codec) charValue(b byte) int {
+	if c.value != nil {
+		return c.value(b)
+	}
+	return strings.IndexByte(c.alphabet, b)
+}
+
+// radixCodec encodes the input as one big number in the base of the alphabet.
+type radixCodec struct {
+	alphabet string
+	checksum bool
+}
+
+func (c radixCodec) encode(dst, src []byte, final bool) ([]byte, int) {
+	if !final {
+		return dst, 0
+	}
+	n := len(src)
+	if c.checksum {
+		src = append(src[:n:n], base58Checksum(src)...)
+	}
+
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+	base := len(c.alphabet)
+	// Digits in little-endian order.
+	var digits []byte
+	for _, b := range src[zeros:] {
+		carry := int(b)
+		for j := range digits {
+			carry += int(digits[j]) << 8
+			digits[j] = byte(carry % base)
+			carry /= base
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%base))
+			carry /= base
+		}
+	}
+
+	for i := 0; i < zeros; i++ {
+		dst = append(dst, c.alphabet[0])
+	}
+	for j := len(digits) - 1; j >= 0; j-- {
+		dst = append(dst, c.alphabet[digits[j]])
+	}
+	return dst, n
+}
+
+func (c radixCodec) decode(dst, src []byte, final bool) ([]byte, int, error) {
+	if !final {
+		return dst, 0, nil
+	}
+
+	zeros := 0
+	for zeros < len(src) && src[zeros] == c.alphabet[0] {
+		zeros++
+	}
+	base := len(c.alphabet)
+	// Bytes in little-endian order.
+	var number []byte
+	for i := zeros; i < len(src); i++ {
+		carry := strings.IndexByte(c.alphabet, src[i])
+		if carry < 0 {
+			return dst, 0, invalidChar(i, src[i])
+		}
+		for j := range number {
+			carry += int(number[j]) * base
+			number[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			number = append(number, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	decoded := make([]byte, zeros, zeros+len(number))
+	for j := len(number) - 1; j >= 0; j-- {
+		decoded = append(decoded, number[j])
+	}
+	if c.checksum {
+		if len(decoded) < 4 {
+			return dst, 0, &DecodeError{Offset: 0, Reason: "missing checksum"}
+		}
+		payload, sum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+		if !Secret(sum).Equal(base58Checksum(payload)) {
+			return dst, 0, &DecodeError{Offset: 0, Reason: "checksum mismatch"}
+		}
+		decoded = payload
+	}
+	return append(dst, decoded...), len(src), nil
+}
+
+// base58Checksum returns the first four bytes of the double SHA-256 of data.
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+// ascii85Codec encodes blocks of 4 bytes as 5 characters.
+type ascii85Codec struct{}
+
+func (ascii85Codec) encode(dst, src []byte, final bool) ([]byte, int) {
+	n := len(src)
+	if !final {
+		n -= n % 4
+	}
+	for i := 0; i < n; i += 4 {
+		block := src[i:]
+		if len(block) > 4 {
+			block = block[:4]
+		}
+		var v uint32
+		for j := 0; j < 4; j++ {
+			v <<= 8
+			if j < len(block) {
+				v |= uint32(block[j])
+			}
+		}
+		if v == 0 && len(block) == 4 {
+			dst = append(dst, 'z')
+			continue
+		}
+		var chars [5]byte
+		for j := 4; j >= 0; j-- {
+			chars[j] = byte(v%85) + '!'
+			v /= 85
+		}
+		dst = append(dst, chars[:len(block)+1]...)
+	}
+	return dst, n
+}
+
+func (ascii85Codec) decode(dst, src []byte, final bool) ([]byte, int, error) {
+	i := 0
+	for i < len(src) {
+		if src[i] == 'z' {
+			dst = append(dst, 0, 0, 0, 0)
+			i++
+			continue
+		}
+		group := src[i:]
+		if len(group) > 5 {
+			group = group[:5]
+		}
+		if len(group) < 5 && !final {
+			break
+		}
+
+		var v uint64
+		for j, c := range group {
+			if c < '!' || c > 'u' {
+				return dst, i, invalidChar(i+j, c)
+			}
+			v = v*85 + uint64(c-'!')
+		}
+		if len(group) == 1 {
+			return dst, i, &DecodeError{Offset: int64(i), Reason: "incomplete group"}
+		}
+		// A partial group is padded with the highest digit.
+		for j := len(group); j < 5; j++ {
+			v = v*85 + 84
+		}
+		if v > 0xffffffff {
+			return dst, i, &DecodeError{Offset: int64(i), Reason: "group out of range"}
+		}
+		for j := 0; j < len(group)-1; j++ {
+			dst = append(dst, byte(v>>(24-8*j)))
+		}
+		i += len(group)
+	}
+	return dst, i, nil
+}
@@ -0,0 +1,185 @@
+package goutil
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var encodings = []struct {
+	name     string
+	encoding Encoding
+}{
+	{"hex", HexEncoding},
+	{"base32", Base32Encoding},
+	{"crockford", CrockfordEncoding},
+	{"base58", Base58Encoding},
+	{"base58check", Base58CheckEncoding},
+	{"base62", Base62Encoding},
+	{"ascii85", Ascii85Encoding},
+}
+
+func TestEncodingVectors(t *testing.T) {
+	address := decodeHex(t, "00f54a5851e9372b87810a8e60cdd2e7cfd80b6e31")
+	tests := []struct {
+		name     string
+		encoding Encoding
+		input    []byte
+		want     string
+	}{
+		{"hex", HexEncoding, []byte("Hi!\x00\xff"), "486921" + "00ff"},
+		{"hex empty", HexEncoding, nil, ""},
+		{"base32", Base32Encoding, []byte("foobar"), "MZXW6YTBOI======"},
+		{"base32 full block", Base32Encoding, []byte("fooba"), "MZXW6YTB"},
+		{"crockford", CrockfordEncoding, []byte("foobar"), "CSQPYRK1E8"},
+		{"base58", Base58Encoding, []byte("Hello World!"), "2NEpo7TZRRrLZSi2U"},
+		{"base58 leading zeros", Base58Encoding, []byte{0, 0, 1}, "112"},
+		{"base58check address", Base58CheckEncoding, address, "1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAs"},
+		{"base62", Base62Encoding, []byte("\x00\x00Hello World!"), "00T8dgcjRGkZ3aysdN"},
+		{"base62 empty", Base62Encoding, nil, ""},
+		{"ascii85", Ascii85Encoding, []byte("Man is distinguished"), "9jqo^BlbD-BleB1DJ+*+F(f,q"},
+		{"ascii85 zeros", Ascii85Encoding, []byte("Hello, World!\x00\x00\x00\x00xyz"), "87cURD_*#4DfTZ)+TMKB!-id8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.encoding.EncodeToString(tt.input); got != tt.want {
+				t.Errorf("EncodeToString() = %q, want %q", got, tt.want)
+			}
+			got, err := tt.encoding.DecodeString(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.input) {
+				t.Errorf("DecodeString() = %x, want %x", got, tt.input)
+			}
+		})
+	}
+}
+
+func TestEncodingMatchesStandardLibrary(t *testing.T) {
+	for n := 0; n < 64; n++ {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i * 37 % 7 * n)
+		}
+		if got, want := HexEncoding.EncodeToString(data), hex.EncodeToString(data); got != want {
+			t.Errorf("hex of %x = %s, want %s", data, got, want)
+		}
+		if got, want := Base32Encoding.EncodeToString(data), base32.StdEncoding.EncodeToString(data); got != want {
+			t.Errorf("base32 of %x = %s, want %s", data, got, want)
+		}
+		want := make([]byte, ascii85.MaxEncodedLen(n))
+		want = want[:ascii85.Encode(want, data)]
+		if got := Ascii85Encoding.EncodeToString(data); got != string(want) {
+			t.Errorf("ascii85 of %x = %s, want %s", data, got, want)
+		}
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	for _, enc := range encodings {
+		t.Run(enc.name, func(t *testing.T) {
+			for n := 0; n < 40; n++ {
+				data := make([]byte, n)
+				for i := range data {
+					data[i] = byte(255 - i*n)
+				}
+				if n%3 == 0 && n > 0 {
+					data[0] = 0
+				}
+				decoded, err := enc.encoding.DecodeString(enc.encoding.EncodeToString(data))
+				if err != nil {
+					t.Fatalf("DecodeString() of %d bytes: %v", n, err)
+				}
+				if !bytes.Equal(decoded, data) {
+					t.Errorf("round trip of %x = %x", data, decoded)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodingStreams(t *testing.T) {
+	data := bytes.Repeat([]byte("The quick brown fox \x00\x00\x00\x00 jumps"), 50)
+	for _, enc := range encodings {
+		t.Run(enc.name, func(t *testing.T) {
+			var encoded bytes.Buffer
+			w := enc.encoding.NewEncoder(&encoded)
+			for i := 0; i < len(data); i += 7 {
+				end := i + 7
+				if end > len(data) {
+					end = len(data)
+				}
+				if _, err := w.Write(data[i:end]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if want := enc.encoding.EncodeToString(data); encoded.String() != want {
+				t.Fatalf("encoder wrote %q, want %q", encoded.String(), want)
+			}
+
+			decoded, err := io.ReadAll(enc.encoding.NewDecoder(iotest.OneByteReader(&encoded)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decoded, data) {
+				t.Errorf("decoder read %q, want %q", decoded, data)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		encoding   Encoding
+		input      string
+		wantOffset int64
+	}{
+		{"hex invalid character", HexEncoding, "00ag", 3},
+		{"hex odd length", HexEncoding, "00a", 2},
+		{"hex whitespace", HexEncoding, "00 a", 2},
+		{"base32 lower case", Base32Encoding, "MZXW6ytb", 5},
+		{"base32 missing padding", Base32Encoding, "MZXW6YTBOI", 8},
+		{"base32 invalid padding", Base32Encoding, "MZXW6Y==", 6},
+		{"base32 data after padding", Base32Encoding, "MZXW6YTBOI======MZXW6YTB", 16},
+		{"base32 character after padding", Base32Encoding, "MZXW6YTBOI=====A", 15},
+		{"base32 trailing bits", Base32Encoding, "MZXW6YTBOJ======", 9},
+		{"crockford invalid character", CrockfordEncoding, "CSQPYRK1U8", 8},
+		{"crockford incomplete group", CrockfordEncoding, "CSQPYRK1E", 9},
+		{"base58 invalid character", Base58Encoding, "2NEpo7T0RR", 7},
+		{"base58check checksum", Base58CheckEncoding, "1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAt", 0},
+		{"base58check too short", Base58CheckEncoding, "2", 0},
+		{"base62 invalid character", Base62Encoding, "abc-", 3},
+		{"ascii85 invalid character", Ascii85Encoding, "9jqo^Blb~-", 8},
+		{"ascii85 z inside group", Ascii85Encoding, "9jqo^Blzb", 7},
+		{"ascii85 single character", Ascii85Encoding, "9jqo^B", 5},
+		{"ascii85 out of range", Ascii85Encoding, "9jqo^uuuuu", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.encoding.DecodeString(tt.input)
+			var de *DecodeError
+			if !errors.As(err, &de) || !errors.Is(err, ErrInvalidEncoding) {
+				t.Fatalf("DecodeString(%q) = %v, want a DecodeError", tt.input, err)
+			}
+			if de.Offset != tt.wantOffset {
+				t.Errorf("DecodeString(%q) offset = %d, want %d (%v)", tt.input, de.Offset, tt.wantOffset, err)
+			}
+
+			_, err = io.ReadAll(tt.encoding.NewDecoder(iotest.HalfReader(strings.NewReader(tt.input))))
+			if !errors.As(err, &de) || de.Offset != tt.wantOffset {
+				t.Errorf("decoder error = %v, want offset %d", err, tt.wantOffset)
+			}
+		})
+	}
+}
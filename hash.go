@@ -0,0 +1,175 @@
+package goutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// HashAlgorithm identifies a hash function.
+type HashAlgorithm int
+
+// Supported hash functions. Only the cryptographic ones are suitable for integrity checks against
+// tampering and for HMAC; the others are fast checksums for detecting accidental changes.
+const (
+	HashSHA256 HashAlgorithm = iota + 1
+	HashSHA512
+	// HashBLAKE2b256 is BLAKE2b with a 32 bytes digest.
+	HashBLAKE2b256
+	// HashBLAKE2b512 is BLAKE2b with a 64 bytes digest.
+	HashBLAKE2b512
+	// HashCRC32 is CRC-32 with the IEEE polynomial. It is not cryptographic.
+	HashCRC32
+	// HashFNV64a is 64 bit FNV-1a. It is not cryptographic.
+	HashFNV64a
+	// HashXXH64 is 64 bit xxHash with a zero seed. It is not cryptographic.
+	HashXXH64
+)
+
+// String returns the name of the algorithm.
+func (a HashAlgorithm) String() string {
+	switch a {
+	case HashSHA256:
+		return "SHA-256"
+	case HashSHA512:
+		return "SHA-512"
+	case HashBLAKE2b256:
+		return "BLAKE2b-256"
+	case HashBLAKE2b512:
+		return "BLAKE2b-512"
+	case HashCRC32:
+		return "CRC-32"
+	case HashFNV64a:
+		return "FNV-1a-64"
+	case HashXXH64:
+		return "XXH64"
+	default:
+		return fmt.Sprintf("HashAlgorithm(%d)", int(a))
+	}
+}
+
+// Cryptographic reports whether the algorithm is a cryptographic hash function.
+func (a HashAlgorithm) Cryptographic() bool {
+	switch a {
+	case HashSHA256, HashSHA512, HashBLAKE2b256, HashBLAKE2b512:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewHash returns a new hash.Hash computing the given algorithm.
+func NewHash(alg HashAlgorithm) (hash.Hash, error) {
+	switch alg {
+	case HashSHA256:
+		return sha256.New(), nil
+	case HashSHA512:
+		return sha512.New(), nil
+	case HashBLAKE2b256:
+		return blake2b.New256(nil)
+	case HashBLAKE2b512:
+		return blake2b.New512(nil)
+	case HashCRC32:
+		return crc32.NewIEEE(), nil
+	case HashFNV64a:
+		return fnv.New64a(), nil
+	case HashXXH64:
+		return newXXH64(0), nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// Digest is the output of a hash function.
+type Digest []byte
+
+// Hex returns the digest hex encoded.
+func (d Digest) Hex() string {
+	return hex.EncodeToString(d)
+}
+
+// Base64 returns the digest URL-safe Base64 encoded, padded like GenerateToken.
+func (d Digest) Base64() string {
+	return base64.URLEncoding.EncodeToString(d)
+}
+
+// String returns the digest hex encoded.
+func (d Digest) String() string {
+	return d.Hex()
+}
+
+// HashBytes returns the digest of data.
+func HashBytes(alg HashAlgorithm, data []byte) (Digest, error) {
+	h, err := NewHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// HashString returns the digest of s.
+func HashString(alg HashAlgorithm, s string) (Digest, error) {
+	return HashReader(alg, strings.NewReader(s))
+}
+
+// HashReader returns the digest of everything read from r until io.EOF.
+func HashReader(alg HashAlgorithm, r io.Reader) (Digest, error) {
+	h, err := NewHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// HashFile returns the digest of the content of the file at path.
+func HashFile(alg HashAlgorithm, path string) (Digest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return HashReader(alg, f)
+}
+
+// HMAC returns the HMAC of data keyed with key, using a cryptographic hash function.
+func HMAC(alg HashAlgorithm, key, data []byte) (Digest, error) {
+	if !alg.Cryptographic() {
+		return nil, fmt.Errorf("%w: %v is not suitable for HMAC", ErrUnsupportedAlgorithm, alg)
+	}
+	mac := hmac.New(func() hash.Hash {
+		h, _ := NewHash(alg)
+		return h
+	}, key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// VerifyHMAC reports whether mac is the HMAC of data keyed with key, comparing in constant time.
+func VerifyHMAC(alg HashAlgorithm, key, data, mac []byte) (bool, error) {
+	expected, err := HMAC(alg, key, data)
+	if err != nil {
+		return false, err
+	}
+	return HashEqual(expected, mac), nil
+}
+
+// HashEqual reports whether two digests are equal. The time taken depends only on the lengths,
+// so it is safe for comparing secret digests such as MACs.
+func HashEqual(a, b []byte) bool {
+	return Secret(a).Equal(b)
+}
@@ -0,0 +1,129 @@
+package goutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHashAlgorithms(t *testing.T) {
+	tests := []struct {
+		alg  HashAlgorithm
+		want string
+	}{
+		{HashSHA256, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{HashSHA512, "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f"},
+		{HashBLAKE2b256, "256c83b297114d201b30179f3f0ef0cace9783622da5974326b436178aeef610"},
+		{HashBLAKE2b512, "021ced8799296ceca557832ab941a50b4a11f83478cf141f51f933f653ab9fbcc05a037cddbed06e309bf334942c4e58cdf1a46e237911ccd7fcf9787cbc7fd0"},
+		{HashCRC32, "0d4a1185"},
+		{HashFNV64a, "779a65e7023cd2e7"},
+		{HashXXH64, "45ab6734b21e6968"},
+	}
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello world"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg.String(), func(t *testing.T) {
+			digest, err := HashBytes(tt.alg, []byte("hello world"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if digest.Hex() != tt.want {
+				t.Errorf("HashBytes() = %s, want %s", digest, tt.want)
+			}
+
+			digest, err = HashString(tt.alg, "hello world")
+			if err != nil || digest.Hex() != tt.want {
+				t.Errorf("HashString() = %s, %v, want %s", digest, err, tt.want)
+			}
+			digest, err = HashReader(tt.alg, iotest.OneByteReader(strings.NewReader("hello world")))
+			if err != nil || digest.Hex() != tt.want {
+				t.Errorf("HashReader() = %s, %v, want %s", digest, err, tt.want)
+			}
+			digest, err = HashFile(tt.alg, path)
+			if err != nil || digest.Hex() != tt.want {
+				t.Errorf("HashFile() = %s, %v, want %s", digest, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashErrors(t *testing.T) {
+	if _, err := HashBytes(HashAlgorithm(0), nil); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("HashBytes() with unknown algorithm error = %v, want ErrUnsupportedAlgorithm", err)
+	}
+	readErr := errors.New("read failed")
+	if _, err := HashReader(HashSHA256, iotest.ErrReader(readErr)); !errors.Is(err, readErr) {
+		t.Errorf("HashReader() error = %v, want %v", err, readErr)
+	}
+	if _, err := HashFile(HashSHA256, filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("HashFile() error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestDigestBase64(t *testing.T) {
+	digest, err := HashString(HashSHA256, "hello world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := digest.Base64(), "uU0nuZNNPgilLlLX2n2r-sSE7-N6U4DukIj3rOLvzek="; got != want {
+		t.Errorf("Base64() = %s, want %s", got, want)
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	key := []byte("key")
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	tests := []struct {
+		alg  HashAlgorithm
+		want string
+	}{
+		{HashSHA256, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"},
+		{HashSHA512, "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg.String(), func(t *testing.T) {
+			mac, err := HMAC(tt.alg, key, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mac.Hex() != tt.want {
+				t.Errorf("HMAC() = %s, want %s", mac, tt.want)
+			}
+			if ok, err := VerifyHMAC(tt.alg, key, data, mac); !ok || err != nil {
+				t.Errorf("VerifyHMAC() = %v, %v, want true", ok, err)
+			}
+			if ok, _ := VerifyHMAC(tt.alg, []byte("other key"), data, mac); ok {
+				t.Error("VerifyHMAC() accepted a MAC for another key")
+			}
+		})
+	}
+
+	if _, err := HMAC(HashCRC32, key, data); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("HMAC() with CRC-32 error = %v, want ErrUnsupportedAlgorithm", err)
+	}
+}
+
+func TestHashEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []byte
+		want bool
+	}{
+		{"equal", []byte{1, 2, 3}, []byte{1, 2, 3}, true},
+		{"different", []byte{1, 2, 3}, []byte{1, 2, 4}, false},
+		{"prefix", []byte{1, 2, 3}, []byte{1, 2}, false},
+		{"empty", nil, []byte{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HashEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("HashEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package goutil
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+// JWTAlgorithm is the signature algorithm of a JSON Web Token.
+type JWTAlgorithm string
+
+// Supported JWT signature algorithms. The unsecured algorithm "none" is always rejected.
+const (
+	// JWTHS256 is HMAC with SHA-256, using a []byte or Secret key of at least 32 bytes.
+	JWTHS256 JWTAlgorithm = "HS256"
+	// JWTHS512 is HMAC with SHA-512, using a []byte or Secret key of at least 64 bytes.
+	JWTHS512 JWTAlgorithm = "HS512"
+	// JWTEdDSA is Ed25519, signing with an ed25519.PrivateKey and verifying with an ed25519.PublicKey.
+	JWTEdDSA JWTAlgorithm = "EdDSA"
+)
+
+var (
+	// ErrTokenNotYetValid is returned when a token is used before its "nbf" claim.
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+	// ErrInvalidClaims is returned when the issuer or audience of a token do not match.
+	ErrInvalidClaims = errors.New("invalid token claims")
+)
+
+// JWTClaims are the registered claims of a JSON Web Token as specified in RFC 7519.
+// Times are Unix timestamps; zero values are omitted.
+// Embed it in a struct to add private claims.
+type JWTClaims struct {
+	Issuer    string      `json:"iss,omitempty"`
+	Subject   string      `json:"sub,omitempty"`
+	Audience  JWTAudience `json:"aud,omitempty"`
+	ExpiresAt int64       `json:"exp,omitempty"`
+	NotBefore int64       `json:"nbf,omitempty"`
+	IssuedAt  int64       `json:"iat,omitempty"`
+	ID        string      `json:"jti,omitempty"`
+}
+
+// NewJWTClaims returns claims for the subject, issued now and expiring after ttl.
+func NewJWTClaims(subject string, ttl time.Duration) JWTClaims {
+	now := time.Now()
+	return JWTClaims{
+		Subject:   subject,
+		IssuedAt:  TimeToUnix(now),
+		ExpiresAt: TimeToUnix(now.Add(ttl)),
+	}
+}
+
+// JWTAudience is the "aud" claim. It is encoded as a string if it has a single element.
+type JWTAudience []string
+
+// MarshalJSON encodes a single audience as a string and multiple audiences as an array.
+func (a JWTAudience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON decodes a string or an array of strings.
+func (a *JWTAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = JWTAudience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+type jwtHeader struct {
+	Algorithm JWTAlgorithm `json:"alg"`
+	Type      string       `json:"typ,omitempty"`
+}
+
+// SignJWT returns the claims as a JSON Web Token in compact serialization, signed with the key.
+// The claims can be JWTClaims or any value encoding to a JSON object, e.g. a struct embedding JWTClaims.
+func SignJWT(claims any, alg JWTAlgorithm, key any) (string, error) {
+	header, err := json.Marshal(jwtHeader{Algorithm: alg, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := jwtSign(alg, key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyJWT verifies a token with the default JWTVerifier and decodes its claims into claims, if not nil.
+func VerifyJWT(token string, alg JWTAlgorithm, key any, claims any) error {
+	return JWTVerifier{Algorithm: alg, Key: key}.Verify(token, claims)
+}
+
+// JWTVerifier verifies JSON Web Tokens signed with a single algorithm and key.
+type JWTVerifier struct {
+	// Algorithm is the expected algorithm. Tokens with another "alg" header are rejected.
+	Algorithm JWTAlgorithm
+	// Key is a []byte or Secret for HMAC or an ed25519.PublicKey for EdDSA.
+	Key any
+	// Issuer is the expected "iss" claim, if not empty.
+	Issuer string
+	// Audience must be contained in the "aud" claim, if not empty.
+	Audience string
+	// Leeway is the time tolerated for clock differences when checking "exp" and "nbf".
+	Leeway time.Duration
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Verify checks the signature and the registered claims of a token and decodes its claims into claims, if not nil.
+func (v JWTVerifier) Verify(token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: expected three parts", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return err
+	}
+	if strings.EqualFold(string(header.Algorithm), "none") {
+		return fmt.Errorf("%w: unsecured tokens are not accepted", ErrInvalidToken)
+	}
+	if header.Algorithm != v.Algorithm {
+		return fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	signingInput := []byte(token[:len(parts[0])+1+len(parts[1])])
+	if err := jwtVerify(v.Algorithm, v.Key, signingInput, signature); err != nil {
+		return err
+	}
+
+	var registered JWTClaims
+	if err := decodeJWTPart(parts[1], &registered); err != nil {
+		return err
+	}
+	if err := v.validate(registered); err != nil {
+		return err
+	}
+	if claims == nil {
+		return nil
+	}
+	return decodeJWTPart(parts[1], claims)
+}
+
+func (v JWTVerifier) validate(claims JWTClaims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if claims.ExpiresAt != 0 && now.After(UnixToTime(claims.ExpiresAt).Add(v.Leeway)) {
+		return fmt.Errorf("%w: at %v", ErrTokenExpired, UnixToTime(claims.ExpiresAt))
+	}
+	if claims.NotBefore != 0 && now.Add(v.Leeway).Before(UnixToTime(claims.NotBefore)) {
+		return fmt.Errorf("%w: until %v", ErrTokenNotYetValid, UnixToTime(claims.NotBefore))
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidClaims, claims.Issuer)
+	}
+	if v.Audience != "" {
+		for _, aud := range claims.Audience {
+			if aud == v.Audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: audience %q not found", ErrInvalidClaims, v.Audience)
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return nil
+}
+
+func jwtSign(alg JWTAlgorithm, key any, signingInput []byte) ([]byte, error) {
+	switch alg {
+	case JWTHS256, JWTHS512:
+		h, err := jwtHMAC(alg, key)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(signingInput)
+		return h.Sum(nil), nil
+	case JWTEdDSA:
+		priv, ok := key.(ed25519.PrivateKey)
+		if !ok || len(priv) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("%w: EdDSA requires an ed25519.PrivateKey", ErrInvalidKey)
+		}
+		return ed25519.Sign(priv, signingInput), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+func jwtVerify(alg JWTAlgorithm, key any, signingInput, signature []byte) error {
+	switch alg {
+	case JWTHS256, JWTHS512:
+		h, err := jwtHMAC(alg, key)
+		if err != nil {
+			return err
+		}
+		h.Write(signingInput)
+		if !Secret(signature).Equal(h.Sum(nil)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case JWTEdDSA:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || len(pub) != ed25519.PublicKeySize {
+			return fmt.Errorf("%w: EdDSA requires an ed25519.PublicKey", ErrInvalidKey)
+		}
+		if !ed25519.Verify(pub, signingInput, signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// jwtHMAC returns the keyed HMAC for the algorithm. The key must be at least as long as the hash.
+func jwtHMAC(alg JWTAlgorithm, key any) (hash.Hash, error) {
+	var secret []byte
+	switch k := key.(type) {
+	case []byte:
+		secret = k
+	case Secret:
+		secret = k
+	default:
+		return nil, fmt.Errorf("%w: %s requires a []byte key", ErrInvalidKey, alg)
+	}
+	h := sha256.New
+	if alg == JWTHS512 {
+		h = sha512.New
+	}
+	mac := hmac.New(h, secret)
+	if len(secret) < mac.Size() {
+		return nil, fmt.Errorf("%w: %s requires a key of at least %d bytes", ErrInvalidKey, alg, mac.Size())
+	}
+	return mac, nil
+}
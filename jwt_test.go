@@ -0,0 +1,168 @@
+package goutil
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Example from RFC 7515, appendix A.1.
+func TestVerifyJWTExample(t *testing.T) {
+	key, err := base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	var claims struct {
+		JWTClaims
+		Root bool `json:"http://example.com/is_root"`
+	}
+	v := JWTVerifier{Algorithm: JWTHS256, Key: key, Issuer: "joe", Now: func() time.Time { return time.Unix(1300819000, 0) }}
+	if err := v.Verify(token, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Issuer != "joe" || claims.ExpiresAt != 1300819380 || !claims.Root {
+		t.Errorf("Verify() claims = %+v", claims)
+	}
+
+	if err := VerifyJWT(token, JWTHS256, key, nil); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("VerifyJWT() = %v, want ErrTokenExpired", err)
+	}
+}
+
+func TestSignJWT(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := bytes.Repeat([]byte("k"), 64)
+
+	tests := []struct {
+		alg       JWTAlgorithm
+		signKey   any
+		verifyKey any
+	}{
+		{JWTHS256, secret[:32], secret[:32]},
+		{JWTHS512, secret, secret},
+		{JWTEdDSA, priv, pub},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.alg), func(t *testing.T) {
+			claims := NewJWTClaims("user-1", time.Hour)
+			claims.Audience = JWTAudience{"api"}
+			token, err := SignJWT(claims, tt.alg, tt.signKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.ContainsAny(token, "+/=") {
+				t.Errorf("SignJWT() = %q is not base64url encoded", token)
+			}
+
+			var got JWTClaims
+			if err := (JWTVerifier{Algorithm: tt.alg, Key: tt.verifyKey, Audience: "api"}).Verify(token, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Subject != "user-1" || len(got.Audience) != 1 || got.Audience[0] != "api" {
+				t.Errorf("Verify() claims = %+v", got)
+			}
+
+			tampered := token[:len(token)-4] + "AAAA"
+			if err := VerifyJWT(tampered, tt.alg, tt.verifyKey, nil); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifyJWT() of tampered token = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierClaims(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+	now := time.Unix(1700000000, 0)
+	token, err := SignJWT(JWTClaims{
+		Issuer:    "auth",
+		Audience:  JWTAudience{"api", "web"},
+		NotBefore: TimeToUnix(now),
+		ExpiresAt: TimeToUnix(now.Add(time.Hour)),
+	}, JWTHS256, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier JWTVerifier
+		wantErr  error
+	}{
+		{"valid", JWTVerifier{Issuer: "auth", Audience: "web"}, nil},
+		{"wrong issuer", JWTVerifier{Issuer: "other"}, ErrInvalidClaims},
+		{"wrong audience", JWTVerifier{Audience: "admin"}, ErrInvalidClaims},
+		{"not yet valid", JWTVerifier{Now: func() time.Time { return now.Add(-time.Second) }}, ErrTokenNotYetValid},
+		{"leeway before", JWTVerifier{Leeway: time.Minute, Now: func() time.Time { return now.Add(-time.Second) }}, nil},
+		{"expired", JWTVerifier{Now: func() time.Time { return now.Add(time.Hour + time.Second) }}, ErrTokenExpired},
+		{"leeway after", JWTVerifier{Leeway: time.Minute, Now: func() time.Time { return now.Add(time.Hour + time.Second) }}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.verifier
+			v.Algorithm, v.Key = JWTHS256, key
+			if v.Now == nil {
+				v.Now = func() time.Time { return now }
+			}
+			if err := v.Verify(token, nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyJWTErrors(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 64)
+	token, err := SignJWT(JWTClaims{Subject: "user-1"}, JWTHS256, key[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	encode := func(v any) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		alg     JWTAlgorithm
+		wantErr error
+	}{
+		{"two parts", parts[0] + "." + parts[1], JWTHS256, ErrInvalidToken},
+		{"alg none", encode(map[string]string{"alg": "none"}) + "." + parts[1] + ".", JWTHS256, ErrInvalidToken},
+		{"alg None", encode(map[string]string{"alg": "None"}) + "." + parts[1] + ".", "None", ErrInvalidToken},
+		{"algorithm mismatch", token, JWTHS512, ErrInvalidToken},
+		{"invalid header", "e30x." + parts[1] + "." + parts[2], JWTHS256, ErrInvalidToken},
+		{"invalid signature encoding", parts[0] + "." + parts[1] + ".!", JWTHS256, ErrInvalidToken},
+		{"unsupported algorithm", encode(map[string]string{"alg": "RS256"}) + "." + parts[1] + "." + parts[2], "RS256", ErrUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyJWT(tt.token, tt.alg, key[:32], nil); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyJWT() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := SignJWT(JWTClaims{}, JWTHS256, key[:31]); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("SignJWT() with short key = %v, want ErrInvalidKey", err)
+	}
+	if _, err := SignJWT(JWTClaims{}, JWTEdDSA, key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("SignJWT() with HMAC key for EdDSA = %v, want ErrInvalidKey", err)
+	}
+	if _, err := SignJWT(JWTClaims{}, "none", key); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("SignJWT() with alg none = %v, want ErrUnsupportedAlgorithm", err)
+	}
+}
@@ -0,0 +1,204 @@
+package goutil
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// keyringEnvelopeVersion is the version of the ciphertext format produced by a Keyring.
+// A ciphertext is laid out as version (1 byte), algorithm (1 byte), key id (4 bytes, big endian),
+// nonce and sealed plaintext.
+const keyringEnvelopeVersion = 2
+
+var (
+	// ErrUnknownKey is returned when a key id is not part of a keyring.
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrInvalidKeyLength is returned when a key of the requested length cannot be derived.
+	ErrInvalidKeyLength = errors.New("invalid key length")
+)
+
+// DeriveKey derives a subkey of length bytes from a master secret using HKDF-SHA256 (RFC 5869).
+// Different contexts, e.g. "session-cookie" or "backup-encryption", yield independent subkeys.
+// The salt is optional; a random salt strengthens the derivation if the secret has little entropy.
+func DeriveKey(secret, salt []byte, context string, length int) ([]byte, error) {
+	if length <= 0 || length > 255*sha256.Size {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidKeyLength, length)
+	}
+	key := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(context)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Keyring holds versioned keys to encrypt with the primary key and decrypt with any known key,
+// so keys can be rotated without re-encrypting existing data at once.
+// It is safe for concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	alg     Algorithm
+	keys    map[uint32]Secret
+	primary uint32
+	// nextID is one higher than every id ever added, so Rotate never reuses the id of a retired key.
+	nextID uint64
+}
+
+// NewKeyring returns an empty keyring encrypting with the given algorithm.
+func NewKeyring(alg Algorithm) (*Keyring, error) {
+	if alg != AESGCM && alg != ChaCha20Poly1305 {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedAlgorithm, alg)
+	}
+	return &Keyring{alg: alg, keys: make(map[uint32]Secret)}, nil
+}
+
+// Add adds a key with the given id. The first key added becomes the primary key.
+func (k *Keyring) Add(id uint32, key []byte) error {
+	if _, err := newAEAD(k.alg, key); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("%w: key %d already exists", ErrInvalidKey, id)
+	}
+	k.keys[id] = NewSecret(key)
+	if len(k.keys) == 1 {
+		k.primary = id
+	}
+	if uint64(id) >= k.nextID {
+		k.nextID = uint64(id) + 1
+	}
+	return nil
+}
+
+// Rotate adds a random key with an id one higher than all ids ever added and makes it the primary key.
+// Ids of retired keys are never reused.
+// Options select another RandSource for the key, e.g. a seeded one in tests.
+func (k *Keyring) Rotate(opts ...RandOption) (uint32, error) {
+	key, err := GenerateKey(opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.nextID == 0 {
+		k.nextID = 1
+	}
+	if k.nextID > math.MaxUint32 {
+		return 0, fmt.Errorf("%w: no key id left", ErrInvalidKey)
+	}
+	id := uint32(k.nextID)
+	k.keys[id] = key
+	k.primary = id
+	k.nextID++
+	return id, nil
+}
+
+// SetPrimary makes the key with the given id the primary key used for encryption.
+func (k *Keyring) SetPrimary(id uint32) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownKey, id)
+	}
+	k.primary = id
+	return nil
+}
+
+// Retire removes and zeroes the key with the given id, so ciphertexts referencing it can no longer be decrypted.
+// The primary key cannot be retired.
+func (k *Keyring) Retire(id uint32) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownKey, id)
+	}
+	if id == k.primary {
+		return fmt.Errorf("%w: cannot retire the primary key %d", ErrInvalidKey, id)
+	}
+	k.keys[id].Zero()
+	delete(k.keys, id)
+	return nil
+}
+
+// Primary returns the id of the primary key and whether the keyring has a key at all.
+func (k *Keyring) Primary() (uint32, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.primary, len(k.keys) > 0
+}
+
+// IDs returns the sorted ids of all keys.
+func (k *Keyring) IDs() []uint32 {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	ids := make([]uint32, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// KeyID returns the id of the key a ciphertext produced by Encrypt references.
+func (k *Keyring) KeyID(ciphertext []byte) (uint32, error) {
+	if len(ciphertext) < 6 {
+		return 0, ErrInvalidCiphertext
+	}
+	if ciphertext[0] != keyringEnvelopeVersion {
+		return 0, fmt.Errorf("%w: unknown version %d", ErrInvalidCiphertext, ciphertext[0])
+	}
+	return binary.BigEndian.Uint32(ciphertext[2:6]), nil
+}
+
+// Encrypt encrypts and authenticates the plaintext with the primary key.
+// The additional data is authenticated, but not encrypted; the same data must be passed to Decrypt.
+// The returned ciphertext records the algorithm, the key id and a random nonce.
+func (k *Keyring) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	k.mu.RLock()
+	id := k.primary
+	k.mu.RUnlock()
+	aead, err := k.aead(id, k.alg)
+	if err != nil {
+		return nil, err
+	}
+	header := []byte{keyringEnvelopeVersion, byte(k.alg), 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[2:], id)
+	return seal(aead, header, plaintext, additionalData)
+}
+
+// Decrypt authenticates and decrypts a ciphertext produced by Encrypt with the key it references.
+func (k *Keyring) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	id, err := k.KeyID(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := k.aead(id, Algorithm(ciphertext[1]))
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext[:6], ciphertext[6:], additionalData)
+}
+
+// aead returns the AEAD for the key with the given id. It is set up while holding the lock,
+// as Retire zeroes the key.
+func (k *Keyring) aead(id uint32, alg Algorithm) (cipher.AEAD, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKey, id)
+	}
+	return newAEAD(alg, key)
+}
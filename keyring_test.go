@@ -0,0 +1,224 @@
+package goutil
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+// Test vector from RFC 5869, test case 1.
+func TestHKDF(t *testing.T) {
+	secret := decodeHex(t, "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt := decodeHex(t, "000102030405060708090a0b0c")
+	info := decodeHex(t, "f0f1f2f3f4f5f6f7f8f9")
+	want := decodeHex(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865")
+
+	got, err := DeriveKey(secret, salt, string(info), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("DeriveKey() = %x, want %x", got, want)
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	secret := []byte("master secret")
+	a, err := DeriveKey(secret, nil, "cookies", 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := DeriveKey(secret, nil, "backups", 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, b) {
+		t.Error("DeriveKey() returned the same key for different contexts")
+	}
+	if again, _ := DeriveKey(secret, nil, "cookies", 32); !bytes.Equal(a, again) {
+		t.Error("DeriveKey() is not deterministic")
+	}
+	if long, _ := DeriveKey(secret, nil, "cookies", 64); !bytes.Equal(long[:32], a) {
+		t.Error("DeriveKey() of 64 bytes does not extend the 32 bytes key")
+	}
+
+	for _, length := range []int{0, -1, 255*32 + 1} {
+		if _, err := DeriveKey(secret, nil, "cookies", length); !errors.Is(err, ErrInvalidKeyLength) {
+			t.Errorf("DeriveKey() with length %d = %v, want ErrInvalidKeyLength", length, err)
+		}
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	for _, alg := range []Algorithm{AESGCM, ChaCha20Poly1305} {
+		t.Run(alg.String(), func(t *testing.T) {
+			k, err := NewKeyring(alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := k.Encrypt([]byte("secret"), nil); !errors.Is(err, ErrUnknownKey) {
+				t.Errorf("Encrypt() without keys = %v, want ErrUnknownKey", err)
+			}
+
+			if err := k.Add(7, bytes.Repeat([]byte{1}, 32)); err != nil {
+				t.Fatal(err)
+			}
+			if id, ok := k.Primary(); id != 7 || !ok {
+				t.Errorf("Primary() = %d, %v, want 7, true", id, ok)
+			}
+			old, err := k.Encrypt([]byte("old secret"), []byte("user 1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			id, err := k.Rotate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != 8 {
+				t.Errorf("Rotate() = %d, want 8", id)
+			}
+			current, err := k.Encrypt([]byte("new secret"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := k.KeyID(current); got != 8 {
+				t.Errorf("KeyID() of new ciphertext = %d, want 8", got)
+			}
+
+			if got, err := k.Decrypt(old, []byte("user 1")); err != nil || string(got) != "old secret" {
+				t.Errorf("Decrypt() of old ciphertext = %q, %v", got, err)
+			}
+			if got, err := k.Decrypt(current, nil); err != nil || string(got) != "new secret" {
+				t.Errorf("Decrypt() of new ciphertext = %q, %v", got, err)
+			}
+			if _, err := k.Decrypt(old, []byte("user 2")); !errors.Is(err, ErrDecryptionFailed) {
+				t.Errorf("Decrypt() with wrong additional data = %v, want ErrDecryptionFailed", err)
+			}
+
+			if err := k.Retire(8); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Retire() of primary key = %v, want ErrInvalidKey", err)
+			}
+			if err := k.Retire(7); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := k.Decrypt(old, []byte("user 1")); !errors.Is(err, ErrUnknownKey) {
+				t.Errorf("Decrypt() with retired key = %v, want ErrUnknownKey", err)
+			}
+			if ids := k.IDs(); len(ids) != 1 || ids[0] != 8 {
+				t.Errorf("IDs() = %v, want [8]", ids)
+			}
+		})
+	}
+}
+
+func TestKeyringErrors(t *testing.T) {
+	if _, err := NewKeyring(Algorithm(9)); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("NewKeyring() = %v, want ErrUnsupportedAlgorithm", err)
+	}
+
+	k, err := NewKeyring(ChaCha20Poly1305)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Add(1, make([]byte, 16)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Add() with short key = %v, want ErrInvalidKey", err)
+	}
+	if err := k.Add(1, make([]byte, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Add(1, make([]byte, 32)); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Add() of existing id = %v, want ErrInvalidKey", err)
+	}
+	if err := k.Add(2, make([]byte, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := k.Primary(); id != 1 {
+		t.Errorf("Primary() after second Add = %d, want 1", id)
+	}
+	if err := k.SetPrimary(3); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("SetPrimary() of unknown id = %v, want ErrUnknownKey", err)
+	}
+	if err := k.Retire(3); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Retire() of unknown id = %v, want ErrUnknownKey", err)
+	}
+	if err := k.SetPrimary(2); err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := k.Encrypt([]byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(make([]byte, 32), ciphertext, nil); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("Decrypt() of keyring ciphertext = %v, want ErrInvalidCiphertext", err)
+	}
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[5] = 1
+	if _, err := k.Decrypt(tampered, nil); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("Decrypt() with changed key id = %v, want ErrDecryptionFailed", err)
+	}
+	for _, c := range [][]byte{nil, ciphertext[:5], append([]byte{1}, ciphertext[1:]...), ciphertext[:20]} {
+		if _, err := k.Decrypt(c, nil); !errors.Is(err, ErrInvalidCiphertext) {
+			t.Errorf("Decrypt(%x) = %v, want ErrInvalidCiphertext", c, err)
+		}
+	}
+}
+
+func TestKeyringRotateAfterRetire(t *testing.T) {
+	k, err := NewKeyring(AESGCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := k.Rotate(); err != nil || id != 1 {
+		t.Fatalf("Rotate() of empty keyring = %d, %v, want 1", id, err)
+	}
+	if err := k.Add(2, make([]byte, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Retire(2); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := k.Rotate(); err != nil || id != 3 {
+		t.Errorf("Rotate() after retiring key 2 = %d, %v, want 3", id, err)
+	}
+
+	if err := k.Add(math.MaxUint32, make([]byte, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.Rotate(); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Rotate() after id %d = %v, want ErrInvalidKey", uint32(math.MaxUint32), err)
+	}
+}
+
+func TestKeyringRotateWithRandSource(t *testing.T) {
+	k, err := NewKeyring(ChaCha20Poly1305)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := k.Rotate(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := k.Encrypt([]byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := GenerateKey(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewKeyring(ChaCha20Poly1305)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Add(id, key); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := other.Decrypt(ciphertext, nil); err != nil || string(got) != "secret" {
+		t.Errorf("Decrypt() with the key from the same seed = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,99 @@
+package goutil
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ksuidEpoch is the start of KSUID time, 2014-05-13T16:53:20Z, in Unix seconds.
+const ksuidEpoch = 1400000000
+
+// KSUID is a K-sortable unique identifier: a 32 bit timestamp in seconds since the KSUID epoch
+// followed by 128 random bits, encoded as 27 base62 characters.
+// It is marshalled as text and JSON in its string form.
+type KSUID [20]byte
+
+// NewKSUID returns a KSUID for the current time.
+// Options select another RandSource, e.g. a seeded one in tests.
+func NewKSUID(opts ...RandOption) (KSUID, error) {
+	var k KSUID
+	seconds := TimeToUnix(time.Now()) - ksuidEpoch
+	if seconds < 0 || seconds > 1<<32-1 {
+		return KSUID{}, fmt.Errorf("%w: time out of range", ErrInvalidID)
+	}
+	binary.BigEndian.PutUint32(k[:4], uint32(seconds))
+	if _, err := applyRandOptions(defaultGenerator, opts).Read(k[4:]); err != nil {
+		return KSUID{}, err
+	}
+	return k, nil
+}
+
+// ParseKSUID parses the 27 characters string form of a KSUID.
+func ParseKSUID(s string) (KSUID, error) {
+	if len(s) != 27 {
+		return KSUID{}, fmt.Errorf("%w: %q is not a KSUID", ErrInvalidID, s)
+	}
+
+	// Multiply the big-endian number by 62 and add each digit, failing on overflow.
+	var k KSUID
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(CharsetBase62, s[i])
+		if digit < 0 {
+			return KSUID{}, fmt.Errorf("%w: %q is not a KSUID", ErrInvalidID, s)
+		}
+		carry := uint32(digit)
+		for j := len(k) - 1; j >= 0; j-- {
+			v := uint32(k[j])*62 + carry
+			k[j] = byte(v)
+			carry = v >> 8
+		}
+		if carry != 0 {
+			return KSUID{}, fmt.Errorf("%w: %q is out of range", ErrInvalidID, s)
+		}
+	}
+	return k, nil
+}
+
+// String returns the KSUID in its 27 characters base62 form.
+func (k KSUID) String() string {
+	// Repeatedly divide the big-endian number by 62, collecting the remainders.
+	n := k
+	buf := make([]byte, 27)
+	for i := len(buf) - 1; i >= 0; i-- {
+		var rem uint32
+		for j := range n {
+			v := rem<<8 | uint32(n[j])
+			n[j] = byte(v / 62)
+			rem = v % 62
+		}
+		buf[i] = CharsetBase62[rem]
+	}
+	return string(buf)
+}
+
+// Time returns the creation time of the KSUID.
+func (k KSUID) Time() time.Time {
+	return UnixToTime(int64(binary.BigEndian.Uint32(k[:4])) + ksuidEpoch)
+}
+
+// Payload returns the random part of the KSUID.
+func (k KSUID) Payload() []byte {
+	return append([]byte(nil), k[4:]...)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k KSUID) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *KSUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseKSUID(string(text))
+	if err != nil {
+		return err
+	}
+	*k = parsed
+	return nil
+}
@@ -0,0 +1,94 @@
+package goutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Example from the reference implementation's documentation.
+func TestParseKSUID(t *testing.T) {
+	k, err := ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := decodeHex(t, "0669F7EFB5A1CD34B5F99D1154FB6853345C9735"); !bytes.Equal(k[:], want) {
+		t.Errorf("ParseKSUID() = %x, want %x", k[:], want)
+	}
+	if !k.Time().Equal(time.Unix(1507608047, 0)) {
+		t.Errorf("Time() = %v, want %v", k.Time(), time.Unix(1507608047, 0))
+	}
+	if want := decodeHex(t, "B5A1CD34B5F99D1154FB6853345C9735"); !bytes.Equal(k.Payload(), want) {
+		t.Errorf("Payload() = %x, want %x", k.Payload(), want)
+	}
+	if k.String() != "0ujtsYcgvSTl8PAuAdqWYSMnLOv" {
+		t.Errorf("String() = %s", k)
+	}
+
+	if max, err := ParseKSUID("aWgEPTl1tmebfsQzFP4bxwgy80V"); err != nil || max.String() != "aWgEPTl1tmebfsQzFP4bxwgy80V" {
+		t.Errorf("ParseKSUID() of largest KSUID = %s, %v", max, err)
+	}
+	for _, s := range []string{"", "0ujtsYcgvSTl8PAuAdqWYSMnLO", "0ujtsYcgvSTl8PAuAdqWYSMnLO-", "aWgEPTl1tmebfsQzFP4bxwgy80W"} {
+		if _, err := ParseKSUID(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ParseKSUID(%q) = %v, want ErrInvalidID", s, err)
+		}
+	}
+}
+
+func TestNewKSUID(t *testing.T) {
+	before := time.Now().Truncate(time.Second)
+	a, err := NewKSUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewKSUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Errorf("generated the same KSUID twice: %s", a)
+	}
+	if a.Time().Before(before) || a.Time().After(time.Now()) {
+		t.Errorf("Time() = %v, want a time after %v", a.Time(), before)
+	}
+	if parsed, err := ParseKSUID(a.String()); err != nil || parsed != a {
+		t.Errorf("ParseKSUID(%s) = %s, %v", a, parsed, err)
+	}
+
+	var zero KSUID
+	if zero.String() != "000000000000000000000000000" {
+		t.Errorf("String() of zero KSUID = %s", zero)
+	}
+
+	seeded, err := NewKSUID(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewKSUID(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(seeded.Payload(), again.Payload()) {
+		t.Errorf("KSUIDs from the same seed differ: %s and %s", seeded, again)
+	}
+}
+
+func TestKSUIDJSON(t *testing.T) {
+	k, err := ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"0ujtsYcgvSTl8PAuAdqWYSMnLOv"` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+	var decoded KSUID
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != k {
+		t.Errorf("json.Unmarshal() = %s, %v", decoded, err)
+	}
+}
@@ -0,0 +1,216 @@
+package goutil
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+const (
+	// numberSortThreshold is the length below which SortNumbers uses a comparison sort,
+	// as the passes of radix and counting sort do not pay off for short slices.
+	numberSortThreshold = 256
+	// maxCountingRange is the largest range of values CountingSort allocates counters for.
+	maxCountingRange = 1 << 24
+)
+
+// ErrRangeTooLarge is returned when the values of a slice span too many values for counting sort.
+var ErrRangeTooLarge = errors.New("value range too large for counting sort")
+
+// Take a slice of numbers and sort it in ascending order, choosing the algorithm by type and content:
+// counting sort for integers within a small range, radix sort for other long slices
+// and pattern-defeating quicksort for short ones.
+// NaNs are sorted after all other values and -0 before +0, as by RadixSort.
+func SortNumbers[N Number](slice *[]N) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	data := *slice
+	key, size := numberKey[N]()
+	if len(data) < numberSortThreshold {
+		sortSlice(data, func(a, b N) bool { return key(a) < key(b) })
+		return nil
+	}
+	if !isFloat[N]() {
+		if lo, hi := keyRange(data, key); hi-lo < uint64(len(data)) {
+			countingSortSlice(data, key, lo, hi)
+			return nil
+		}
+	}
+	radixSortSlice(data, key, size)
+	return nil
+}
+
+// Take a slice of numbers and sort it in ascending order with an LSD radix sort,
+// in O(n) time for each byte of the type.
+// Floats are ordered by their IEEE 754 representation: -0 sorts before +0 and NaNs after +Inf.
+// The sort is stable.
+func RadixSort[N Number](slice *[]N) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	key, size := numberKey[N]()
+	radixSortSlice(*slice, key, size)
+	return nil
+}
+
+// Take a slice of integers and sort it in ascending order with counting sort,
+// in O(n + k) time and O(k) space for a range of k values between the minimum and maximum.
+// It returns ErrRangeTooLarge if the range exceeds 2^24 values.
+func CountingSort[I Integer](slice *[]I) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	key, _ := numberKey[I]()
+	lo, hi := keyRange(*slice, key)
+	if hi-lo >= maxCountingRange {
+		return ErrRangeTooLarge
+	}
+	countingSortSlice(*slice, key, lo, hi)
+	return nil
+}
+
+func isFloat[N Number]() bool {
+	var zero N
+	switch any(zero).(type) {
+	case float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// numberKey returns a function mapping numbers of type N to unsigned integers of size bytes,
+// whose order is the order of the numbers. Integers keep their distance, so keys of integers can be counted.
+func numberKey[N Number]() (key func(N) uint64, size int) {
+	var zero N
+	switch any(zero).(type) {
+	case float32:
+		return func(v N) uint64 {
+			if v != v {
+				return math.MaxUint32
+			}
+			// Flip all bits of negative numbers and only the sign bit of positive ones.
+			b := math.Float32bits(float32(v))
+			if b>>31 == 1 {
+				return uint64(^b)
+			}
+			return uint64(b | 1<<31)
+		}, 4
+	case float64:
+		return func(v N) uint64 {
+			if v != v {
+				return math.MaxUint64
+			}
+			b := math.Float64bits(float64(v))
+			if b>>63 == 1 {
+				return ^b
+			}
+			return b | 1<<63
+		}, 8
+	case int8, uint8:
+		size = 1
+	case int16, uint16:
+		size = 2
+	case int32, uint32:
+		size = 4
+	case int, uint:
+		size = bits.UintSize / 8
+	default:
+		size = 8
+	}
+
+	switch any(zero).(type) {
+	case int, int8, int16, int32, int64:
+		// Shift the range of the type to start at 0.
+		offset := uint64(1) << (8*size - 1)
+		mask := ^uint64(0) >> (64 - 8*size)
+		return func(v N) uint64 {
+			return (uint64(int64(v)) + offset) & mask
+		}, size
+	default:
+		return func(v N) uint64 {
+			return uint64(v)
+		}, size
+	}
+}
+
+// keyRange returns the lowest and highest key of the numbers.
+func keyRange[N Number](data []N, key func(N) uint64) (lo, hi uint64) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	lo, hi = key(data[0]), key(data[0])
+	for _, v := range data[1:] {
+		k := key(v)
+		if k < lo {
+			lo = k
+		}
+		if k > hi {
+			hi = k
+		}
+	}
+	return lo, hi
+}
+
+// radixSortSlice sorts data by their keys of size bytes, one byte per pass starting with the least significant.
+func radixSortSlice[N Number](data []N, key func(N) uint64, size int) {
+	if len(data) < 2 {
+		return
+	}
+	src, dst := data, make([]N, len(data))
+	for shift := 0; shift < 8*size; shift += 8 {
+		var offsets [256]int
+		for _, v := range src {
+			offsets[key(v)>>shift&0xff]++
+		}
+		// All numbers share this byte, so the pass would not change the order.
+		if offsets[key(src[0])>>shift&0xff] == len(src) {
+			continue
+		}
+
+		start := 0
+		for i, count := range offsets {
+			offsets[i] = start
+			start += count
+		}
+		for _, v := range src {
+			b := key(v) >> shift & 0xff
+			dst[offsets[b]] = v
+			offsets[b]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
+
+// countingSortSlice sorts integers with keys between lo and hi by counting how often each key occurs.
+func countingSortSlice[N Number](data []N, key func(N) uint64, lo, hi uint64) {
+	if len(data) < 2 {
+		return
+	}
+	counts := make([]int, hi-lo+1)
+	lowest := data[0]
+	for _, v := range data {
+		k := key(v)
+		counts[k-lo]++
+		if k == lo {
+			lowest = v
+		}
+	}
+	// Keys of integers differ by the same amount as the integers, so the value of a key is the lowest
+	// value plus its offset. Converting the offset to N may wrap around, but so does the addition.
+	i := 0
+	for offset, count := range counts {
+		v := lowest + N(offset)
+		for ; count > 0; count-- {
+			data[i] = v
+			i++
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package goutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// testIntegerSorts checks every integer sort against sort.Slice on values drawn from gen.
+func testIntegerSorts[I Integer](t *testing.T, gen func(r *rand.Rand) I) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 10, 255, 256, 5000} {
+		input := make([]I, n)
+		for i := range input {
+			input[i] = gen(r)
+		}
+		want := make([]I, n)
+		copy(want, input)
+		sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+		sorts := map[string]func(*[]I) error{
+			"SortNumbers":  SortNumbers[I],
+			"RadixSort":    RadixSort[I],
+			"CountingSort": CountingSort[I],
+		}
+		for name, sortFn := range sorts {
+			t.Run(fmt.Sprintf("%s %T %d", name, input, n), func(t *testing.T) {
+				got := make([]I, n)
+				copy(got, input)
+				err := sortFn(&got)
+				if errors.Is(err, ErrRangeTooLarge) {
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("element %d = %v, want %v", i, got[i], want[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestIntegerSorts(t *testing.T) {
+	testIntegerSorts(t, func(r *rand.Rand) int8 { return int8(r.Uint32()) })
+	testIntegerSorts(t, func(r *rand.Rand) uint8 { return uint8(r.Uint32()) })
+	testIntegerSorts(t, func(r *rand.Rand) int16 { return int16(r.Uint32()) })
+	testIntegerSorts(t, func(r *rand.Rand) uint16 { return uint16(r.Uint32()) })
+	testIntegerSorts(t, func(r *rand.Rand) int32 { return int32(r.Uint32()) })
+	testIntegerSorts(t, func(r *rand.Rand) uint32 { return r.Uint32() })
+	testIntegerSorts(t, func(r *rand.Rand) int64 { return int64(r.Uint64()) })
+	testIntegerSorts(t, func(r *rand.Rand) uint64 { return r.Uint64() })
+	testIntegerSorts(t, func(r *rand.Rand) int { return int(r.Uint64()) })
+	testIntegerSorts(t, func(r *rand.Rand) uint { return uint(r.Uint64()) })
+	// Small ranges select counting sort, including ranges crossing zero and the type limits.
+	testIntegerSorts(t, func(r *rand.Rand) int { return r.Intn(100) - 50 })
+	testIntegerSorts(t, func(r *rand.Rand) int64 { return math.MaxInt64 - r.Int63n(1000) })
+	testIntegerSorts(t, func(r *rand.Rand) int64 { return math.MinInt64 + r.Int63n(1000) })
+	testIntegerSorts(t, func(r *rand.Rand) uint64 { return math.MaxUint64 - uint64(r.Intn(10)) })
+}
+
+func TestFloatSorts(t *testing.T) {
+	specials := []float64{math.NaN(), math.Inf(1), math.Inf(-1), math.Copysign(0, -1), 0, -math.MaxFloat64, math.SmallestNonzeroFloat64}
+	r := rand.New(rand.NewSource(2))
+	for _, n := range []int{len(specials), 1000} {
+		input := append([]float64(nil), specials...)
+		for len(input) < n {
+			input = append(input, r.NormFloat64()*1e6)
+		}
+		r.Shuffle(len(input), func(i, j int) { input[i], input[j] = input[j], input[i] })
+
+		sorts := map[string]func(*[]float64) error{
+			"SortNumbers": SortNumbers[float64],
+			"RadixSort":   RadixSort[float64],
+		}
+		for name, sortFn := range sorts {
+			t.Run(fmt.Sprintf("%s %d", name, n), func(t *testing.T) {
+				got := append([]float64(nil), input...)
+				if err := sortFn(&got); err != nil {
+					t.Fatal(err)
+				}
+				if !math.IsNaN(got[len(got)-1]) {
+					t.Errorf("last element = %v, want NaN", got[len(got)-1])
+				}
+				if !sort.Float64sAreSorted(got[:len(got)-1]) {
+					t.Errorf("numbers are not sorted: %v", got)
+				}
+				negativeZero, positiveZero := -1, -1
+				for i, v := range got {
+					if v == 0 && math.Signbit(v) {
+						negativeZero = i
+					} else if v == 0 {
+						positiveZero = i
+					}
+				}
+				if negativeZero > positiveZero {
+					t.Errorf("-0 at %d sorted after +0 at %d", negativeZero, positiveZero)
+				}
+			})
+		}
+	}
+
+	float32s := []float32{3, float32(math.NaN()), -1.5, float32(math.Inf(-1)), 0, 2}
+	if err := SortNumbers(&float32s); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(float32s); got != "[-Inf -1.5 0 2 3 NaN]" {
+		t.Errorf("SortNumbers() = %s", got)
+	}
+}
+
+func TestCountingSortRangeTooLarge(t *testing.T) {
+	got := []int64{math.MinInt64, 0, math.MaxInt64}
+	if err := CountingSort(&got); !errors.Is(err, ErrRangeTooLarge) {
+		t.Errorf("CountingSort() error = %v, want ErrRangeTooLarge", err)
+	}
+	if err := SortNumbers(&got); err != nil || !IsSorted(got) {
+		t.Errorf("SortNumbers() = %v, %v", got, err)
+	}
+}
+
+func BenchmarkSortNumbers(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	inputs := map[string][]int{
+		"random":      make([]int, 1e6),
+		"small range": make([]int, 1e6),
+	}
+	for i := 0; i < 1e6; i++ {
+		inputs["random"][i] = r.Int()
+		inputs["small range"][i] = r.Intn(1000)
+	}
+	for name, input := range inputs {
+		data := make([]int, len(input))
+		b.Run("SortNumbers "+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(data, input)
+				SortNumbers(&data)
+			}
+		})
+		b.Run("SortAsc "+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(data, input)
+				SortAsc(&data)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package goutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTPAlgorithm is the HMAC hash function of one-time passwords, named as in otpauth URIs.
+type OTPAlgorithm string
+
+// Supported one-time password algorithms.
+const (
+	OTPSHA1   OTPAlgorithm = "SHA1"
+	OTPSHA256 OTPAlgorithm = "SHA256"
+	OTPSHA512 OTPAlgorithm = "SHA512"
+)
+
+// Default one-time password parameters, as used by common authenticator apps.
+const (
+	defaultOTPDigits       = 6
+	defaultOTPPeriod       = 30 * time.Second
+	defaultOTPSecretLength = 20
+)
+
+// ErrInvalidOTPConfig is returned when one-time password parameters are not supported.
+var ErrInvalidOTPConfig = errors.New("invalid one-time password configuration")
+
+// otpSecretEncoding is base32 without padding, the encoding of otpauth URIs.
+var otpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateOTPSecret returns a random 20 bytes secret, the length recommended by RFC 4226.
+// Options select another RandSource, e.g. a seeded one in tests.
+func GenerateOTPSecret(opts ...RandOption) ([]byte, error) {
+	secret := make([]byte, defaultOTPSecretLength)
+	if _, err := applyRandOptions(defaultGenerator, opts).Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeOTPSecret returns the secret base32 encoded without padding, as shown to users for manual entry.
+func EncodeOTPSecret(secret []byte) string {
+	return otpSecretEncoding.EncodeToString(secret)
+}
+
+// DecodeOTPSecret decodes a base32 encoded secret. Case, spaces and padding are ignored.
+func DecodeOTPSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(s, " ", ""), "="))
+	secret, err := otpSecretEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return secret, nil
+}
+
+// HOTP generates and verifies HMAC-based one-time passwords as specified in RFC 4226.
+type HOTP struct {
+	// Secret is the key shared with the authenticator.
+	Secret []byte
+	// Digits is the number of digits of a code, between 6 and 10. Defaults to 6.
+	Digits int
+	// Algorithm is the HMAC hash function. Defaults to OTPSHA1.
+	Algorithm OTPAlgorithm
+	// Window is the number of counter values after the expected one a code is accepted for,
+	// to resynchronize with authenticators that generated codes without using them.
+	Window int
+}
+
+// Generate returns the code for the counter.
+func (h HOTP) Generate(counter uint64) (string, error) {
+	mac, digits, err := otpParams(h.Secret, h.Algorithm, h.Digits)
+	if err != nil {
+		return "", err
+	}
+	return otpCode(mac, counter, digits), nil
+}
+
+// Verify reports whether the code matches the counter or one of the Window counters following it.
+// If so, it also returns the counter to use for the next verification.
+func (h HOTP) Verify(code string, counter uint64) (next uint64, ok bool, err error) {
+	mac, digits, err := otpParams(h.Secret, h.Algorithm, h.Digits)
+	if err != nil {
+		return counter, false, err
+	}
+	for i := 0; i <= h.Window; i++ {
+		if SecureEqual(otpCode(mac, counter+uint64(i), digits), code) {
+			return counter + uint64(i) + 1, true, nil
+		}
+	}
+	return counter, false, nil
+}
+
+// URI returns an otpauth URI for enrolling the secret with an authenticator app, usually shown as QR code.
+func (h HOTP) URI(issuer, account string, counter uint64) string {
+	params := otpURIParams(h.Secret, issuer, h.Algorithm, h.Digits)
+	params.Set("counter", strconv.FormatUint(counter, 10))
+	return otpURI("hotp", issuer, account, params)
+}
+
+// TOTP generates and verifies time-based one-time passwords as specified in RFC 6238.
+type TOTP struct {
+	// Secret is the key shared with the authenticator.
+	Secret []byte
+	// Digits is the number of digits of a code, between 6 and 10. Defaults to 6.
+	Digits int
+	// Algorithm is the HMAC hash function. Defaults to OTPSHA1.
+	Algorithm OTPAlgorithm
+	// Period is the time step, in whole seconds. Defaults to 30 seconds.
+	Period time.Duration
+	// Window is the number of time steps before and after the current one a code is accepted for,
+	// to tolerate clock drift. A window of 1 is common.
+	Window int
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Generate returns the code for the current time.
+func (t TOTP) Generate() (string, error) {
+	return t.GenerateAt(t.now())
+}
+
+// GenerateAt returns the code for the given time.
+func (t TOTP) GenerateAt(at time.Time) (string, error) {
+	mac, digits, err := otpParams(t.Secret, t.Algorithm, t.Digits)
+	if err != nil {
+		return "", err
+	}
+	step, err := t.step(at)
+	if err != nil {
+		return "", err
+	}
+	return otpCode(mac, step, digits), nil
+}
+
+// Verify reports whether the code matches the current time, allowing for Window time steps of drift.
+// To prevent replays, callers should remember the last accepted step, see VerifyStep.
+func (t TOTP) Verify(code string) (bool, error) {
+	_, ok, err := t.VerifyStep(code)
+	return ok, err
+}
+
+// VerifyStep is like Verify, but also returns the time step the code was generated for.
+func (t TOTP) VerifyStep(code string) (step uint64, ok bool, err error) {
+	mac, digits, err := otpParams(t.Secret, t.Algorithm, t.Digits)
+	if err != nil {
+		return 0, false, err
+	}
+	current, err := t.step(t.now())
+	if err != nil {
+		return 0, false, err
+	}
+	for i := -t.Window; i <= t.Window; i++ {
+		step := current + uint64(i)
+		if i < 0 && current < uint64(-i) {
+			continue
+		}
+		if SecureEqual(otpCode(mac, step, digits), code) {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// URI returns an otpauth URI for enrolling the secret with an authenticator app, usually shown as QR code.
+func (t TOTP) URI(issuer, account string) string {
+	params := otpURIParams(t.Secret, issuer, t.Algorithm, t.Digits)
+	params.Set("period", strconv.FormatInt(int64(t.period()/time.Second), 10))
+	return otpURI("totp", issuer, account, params)
+}
+
+// step returns the number of periods since the Unix epoch.
+func (t TOTP) step(at time.Time) (uint64, error) {
+	period := t.period()
+	if period < time.Second || period%time.Second != 0 {
+		return 0, fmt.Errorf("%w: period must be whole seconds", ErrInvalidOTPConfig)
+	}
+	unix := TimeToUnix(at)
+	if unix < 0 {
+		return 0, fmt.Errorf("%w: time before the Unix epoch", ErrInvalidOTPConfig)
+	}
+	return uint64(unix) / uint64(period/time.Second), nil
+}
+
+func (t TOTP) period() time.Duration {
+	if t.Period == 0 {
+		return defaultOTPPeriod
+	}
+	return t.Period
+}
+
+func (t TOTP) now() time.Time {
+	if t.Now == nil {
+		return time.Now()
+	}
+	return t.Now()
+}
+
+// otpParams validates the parameters and returns the keyed HMAC and the number of digits.
+func otpParams(secret []byte, alg OTPAlgorithm, digits int) (hash.Hash, int, error) {
+	if len(secret) == 0 {
+		return nil, 0, fmt.Errorf("%w: empty secret", ErrInvalidKey)
+	}
+	if digits == 0 {
+		digits = defaultOTPDigits
+	}
+	if digits < 6 || digits > 10 {
+		return nil, 0, fmt.Errorf("%w: %d digits", ErrInvalidOTPConfig, digits)
+	}
+
+	var h func() hash.Hash
+	switch alg {
+	case "", OTPSHA1:
+		h = sha1.New
+	case OTPSHA256:
+		h = sha256.New
+	case OTPSHA512:
+		h = sha512.New
+	default:
+		return nil, 0, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+	return hmac.New(h, secret), digits, nil
+}
+
+// otpCode computes the code for the counter using dynamic truncation.
+func otpCode(mac hash.Hash, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac.Reset()
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff)
+	mod := uint64(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+func otpURIParams(secret []byte, issuer string, alg OTPAlgorithm, digits int) url.Values {
+	if alg == "" {
+		alg = OTPSHA1
+	}
+	if digits == 0 {
+		digits = defaultOTPDigits
+	}
+	params := url.Values{}
+	params.Set("secret", EncodeOTPSecret(secret))
+	if issuer != "" {
+		params.Set("issuer", issuer)
+	}
+	params.Set("algorithm", string(alg))
+	params.Set("digits", strconv.Itoa(digits))
+	return params
+}
+
+// otpURI formats an otpauth URI as understood by authenticator apps.
+func otpURI(kind, issuer, account string, params url.Values) string {
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+	}
+	// Authenticator apps expect spaces encoded as %20 rather than +.
+	query := strings.ReplaceAll(params.Encode(), "+", "%20")
+	u := url.URL{Scheme: "otpauth", Host: kind, Path: "/" + label, RawQuery: query}
+	return u.String()
+}
@@ -0,0 +1,205 @@
+package goutil
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 4226, appendix D.
+func TestHOTP(t *testing.T) {
+	h := HOTP{Secret: []byte("12345678901234567890")}
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		got, err := h.Generate(uint64(counter))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != code {
+			t.Errorf("Generate(%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestHOTPVerify(t *testing.T) {
+	h := HOTP{Secret: []byte("12345678901234567890"), Window: 2}
+	tests := []struct {
+		name     string
+		code     string
+		counter  uint64
+		wantNext uint64
+		wantOK   bool
+	}{
+		{"expected counter", "755224", 0, 1, true},
+		{"within window", "359152", 0, 3, true},
+		{"beyond window", "969429", 0, 0, false},
+		{"already used", "755224", 1, 1, false},
+		{"wrong code", "123456", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok, err := h.Verify(tt.code, tt.counter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != tt.wantNext || ok != tt.wantOK {
+				t.Errorf("Verify() = %d, %v, want %d, %v", next, ok, tt.wantNext, tt.wantOK)
+			}
+		})
+	}
+}
+
+// Test vectors from RFC 6238, appendix B.
+func TestTOTP(t *testing.T) {
+	secrets := map[OTPAlgorithm][]byte{
+		OTPSHA1:   []byte("12345678901234567890"),
+		OTPSHA256: []byte("12345678901234567890123456789012"),
+		OTPSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	tests := []struct {
+		unix int64
+		alg  OTPAlgorithm
+		want string
+	}{
+		{59, OTPSHA1, "94287082"},
+		{59, OTPSHA256, "46119246"},
+		{59, OTPSHA512, "90693936"},
+		{1111111109, OTPSHA1, "07081804"},
+		{1111111109, OTPSHA256, "68084774"},
+		{1111111109, OTPSHA512, "25091201"},
+		{1234567890, OTPSHA1, "89005924"},
+		{2000000000, OTPSHA1, "69279037"},
+		{20000000000, OTPSHA512, "47863826"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.alg), func(t *testing.T) {
+			totp := TOTP{Secret: secrets[tt.alg], Digits: 8, Algorithm: tt.alg}
+			got, err := totp.GenerateAt(UnixToTime(tt.unix))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateAt(%d) = %s, want %s", tt.unix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOTPVerify(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	totp := TOTP{Secret: []byte("12345678901234567890"), Digits: 8, Window: 1, Now: func() time.Time { return now }}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		wantOK bool
+	}{
+		{"current step", now, true},
+		{"previous step", now.Add(-30 * time.Second), true},
+		{"next step", now.Add(30 * time.Second), true},
+		{"two steps ago", now.Add(-60 * time.Second), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totp.GenerateAt(tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			step, ok, err := totp.VerifyStep(code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("VerifyStep() = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != uint64(TimeToUnix(tt.at)/30) {
+				t.Errorf("VerifyStep() step = %d, want %d", step, TimeToUnix(tt.at)/30)
+			}
+		})
+	}
+
+	if ok, err := totp.Verify("00000000"); ok || err != nil {
+		t.Errorf("Verify() of wrong code = %v, %v", ok, err)
+	}
+}
+
+func TestOTPErrors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		name    string
+		totp    TOTP
+		wantErr error
+	}{
+		{"empty secret", TOTP{}, ErrInvalidKey},
+		{"too few digits", TOTP{Secret: secret, Digits: 5}, ErrInvalidOTPConfig},
+		{"too many digits", TOTP{Secret: secret, Digits: 11}, ErrInvalidOTPConfig},
+		{"fractional period", TOTP{Secret: secret, Period: 1500 * time.Millisecond}, ErrInvalidOTPConfig},
+		{"unknown algorithm", TOTP{Secret: secret, Algorithm: "MD5"}, ErrUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.totp.Generate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := tt.totp.Verify("123456"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOTPSecret(t *testing.T) {
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 20 {
+		t.Errorf("GenerateOTPSecret() has %d bytes, want 20", len(secret))
+	}
+	a, err := GenerateOTPSecret(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateOTPSecret(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Errorf("GenerateOTPSecret() from the same seed = %x and %x", a, b)
+	}
+
+	encoded := EncodeOTPSecret([]byte("12345678901234567890"))
+	if encoded != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" {
+		t.Errorf("EncodeOTPSecret() = %s", encoded)
+	}
+	for _, s := range []string{encoded, "gezd gnbv gy3t qojq gezd gnbv gy3t qojq", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ===="} {
+		decoded, err := DecodeOTPSecret(s)
+		if err != nil || !bytes.Equal(decoded, []byte("12345678901234567890")) {
+			t.Errorf("DecodeOTPSecret(%q) = %q, %v", s, decoded, err)
+		}
+	}
+	if _, err := DecodeOTPSecret("not base32!"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("DecodeOTPSecret() of invalid secret = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestOTPURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	totpURI := TOTP{Secret: secret}.URI("Example Co", "alice@example.com")
+	want := "otpauth://totp/Example%20Co:alice@example.com?algorithm=SHA1&digits=6&issuer=Example%20Co&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	if totpURI != want {
+		t.Errorf("TOTP.URI() = %s, want %s", totpURI, want)
+	}
+
+	hotpURI := HOTP{Secret: secret, Digits: 8, Algorithm: OTPSHA256}.URI("", "alice", 5)
+	u, err := url.Parse(hotpURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Host != "hotp" || u.Path != "/alice" || q.Get("counter") != "5" || q.Get("digits") != "8" || q.Get("algorithm") != "SHA256" || q.Has("issuer") {
+		t.Errorf("HOTP.URI() = %s", hotpURI)
+	}
+}
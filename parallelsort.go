@@ -0,0 +1,117 @@
+package goutil
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// defaultParallelThreshold is the length below which ParallelSort sorts sequentially,
+// as spreading shorter slices across goroutines costs more than it saves.
+const defaultParallelThreshold = 1 << 14
+
+// ParallelOption configures ParallelSort and ParallelSortFunc.
+type ParallelOption func(*parallelConfig)
+
+type parallelConfig struct {
+	workers   int
+	threshold int
+}
+
+// WithWorkers sets the maximum number of goroutines sorting in parallel.
+// Values lower than 1 select the default, runtime.GOMAXPROCS(0).
+func WithWorkers(n int) ParallelOption {
+	return func(c *parallelConfig) {
+		c.workers = n
+	}
+}
+
+// WithSequentialThreshold sets the length below which slices are sorted by a single goroutine.
+// Every goroutine sorts at least that many elements. Values lower than 1 select the default of 16384.
+func WithSequentialThreshold(n int) ParallelOption {
+	return func(c *parallelConfig) {
+		c.threshold = n
+	}
+}
+
+// Take a slice of any orderable type and sort it in ascending order using several goroutines.
+// The sort is stable, so the result is identical to StableSortAsc.
+func ParallelSort[C Comparable](slice *[]C, opts ...ParallelOption) error {
+	return ParallelSortFunc(slice, func(a, b C) bool { return a < b }, opts...)
+}
+
+// Take a slice of any type and sort it by less using several goroutines.
+// The slice is split into chunks which are sorted concurrently and merged in rounds.
+// The sort is stable, so the result is identical to StableSortFunc; less must be safe for concurrent use.
+func ParallelSortFunc[A any](slice *[]A, less func(a, b A) bool, opts ...ParallelOption) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	var config parallelConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	parallelSortSlice(*slice, less, config)
+	return nil
+}
+
+func parallelSortSlice[E any](data []E, less func(a, b E) bool, config parallelConfig) {
+	workers := config.workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	threshold := config.threshold
+	if threshold < 1 {
+		threshold = defaultParallelThreshold
+	}
+	chunks := len(data) / threshold
+	if chunks > workers {
+		chunks = workers
+	}
+	if chunks < 2 {
+		stableSortSlice(data, less)
+		return
+	}
+
+	// bounds[i] is the start of the i-th run; the last element is len(data).
+	bounds := make([]int, chunks+1)
+	for i := range bounds {
+		bounds[i] = i * len(data) / chunks
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		wg.Add(1)
+		go func(run []E) {
+			defer wg.Done()
+			stableSortSlice(run, less)
+		}(data[bounds[i]:bounds[i+1]])
+	}
+	wg.Wait()
+
+	// Merge neighbouring runs in rounds, alternating between data and the buffer.
+	src, dst := data, make([]E, len(data))
+	for len(bounds) > 2 {
+		merged := make([]int, 0, len(bounds)/2+1)
+		for i := 0; i+1 < len(bounds); i += 2 {
+			lo := bounds[i]
+			merged = append(merged, lo)
+			if i+2 >= len(bounds) {
+				copy(dst[lo:], src[lo:])
+				continue
+			}
+			mid, hi := bounds[i+1], bounds[i+2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				merge(dst[lo:hi], src[lo:mid], src[mid:hi], less)
+			}()
+		}
+		wg.Wait()
+		bounds = append(merged, len(data))
+		src, dst = dst, src
+	}
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
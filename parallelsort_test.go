@@ -0,0 +1,88 @@
+package goutil
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParallelSortFunc(t *testing.T) {
+	type pair struct{ key, index int }
+	less := func(a, b pair) bool { return a.key < b.key }
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 15, 16, 100, 1000, 54321} {
+		input := make([]pair, n)
+		for i := range input {
+			input[i] = pair{r.Intn(n/8 + 1), i}
+		}
+		want := make([]pair, n)
+		copy(want, input)
+		stableSortSlice(want, less)
+
+		for _, workers := range []int{0, 1, 2, 3, 4, 7, 16} {
+			t.Run(fmt.Sprintf("%d elements %d workers", n, workers), func(t *testing.T) {
+				got := make([]pair, n)
+				copy(got, input)
+				err := ParallelSortFunc(&got, less, WithWorkers(workers), WithSequentialThreshold(16))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Error("result differs from the sequential stable sort")
+				}
+			})
+		}
+	}
+}
+
+func TestParallelSort(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	got := make([]float64, 100000)
+	for i := range got {
+		got[i] = r.NormFloat64()
+	}
+	want := make([]float64, len(got))
+	copy(want, got)
+	sort.Float64s(want)
+
+	if err := ParallelSort(&got, WithWorkers(8), WithSequentialThreshold(1000)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("result differs from sort.Float64s")
+	}
+
+	small := []string{"c", "a", "b"}
+	if err := ParallelSort(&small); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(small, []string{"a", "b", "c"}) {
+		t.Errorf("got %v", small)
+	}
+	if err := ParallelSort[int](nil); err == nil {
+		t.Error("ParallelSort(nil) returned no error")
+	}
+}
+
+func BenchmarkParallelSort(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	input := make([]float64, 1e6)
+	for i := range input {
+		input[i] = r.Float64()
+	}
+	data := make([]float64, len(input))
+	b.Run("SortAsc", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			copy(data, input)
+			SortAsc(&data)
+		}
+	})
+	b.Run("ParallelSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			copy(data, input)
+			ParallelSort(&data)
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package goutil
+
+import (
+	_ "embed"
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+)
+
+//go:embed wordlist.txt
+var wordlistFile string
+
+// wordlist holds 6^4 words, so a word can also be picked by rolling four dice.
+var wordlist = strings.Fields(wordlistFile)
+
+// Character classes of generated passwords.
+const (
+	PasswordUpper     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	PasswordLower     = "abcdefghijklmnopqrstuvwxyz"
+	PasswordDigits    = "0123456789"
+	PasswordSymbols   = "!#$%&()*+,-./:;<=>?@[]^_{}~"
+	PasswordAmbiguous = "0O1Il|"
+)
+
+var (
+	// ErrInvalidWordlist is returned when a wordlist has less than two unique words.
+	ErrInvalidWordlist = errors.New("wordlist must contain at least two unique words")
+	// ErrInvalidPolicy is returned when no password can satisfy a password policy.
+	ErrInvalidPolicy = errors.New("password policy cannot be satisfied")
+)
+
+// Wordlist returns a copy of the embedded wordlist used for passphrases.
+func Wordlist() []string {
+	words := make([]string, len(wordlist))
+	copy(words, wordlist)
+	return words
+}
+
+// PassphraseGenerator generates diceware-style passphrases of random words.
+// The zero value generates six words from the embedded wordlist separated by "-".
+type PassphraseGenerator struct {
+	// Words is the number of words of a passphrase. Defaults to 6.
+	Words int
+	// Separator is put between the words. Defaults to "-".
+	Separator string
+	// Capitalize upper cases the first letter of every word.
+	Capitalize bool
+	// Wordlist is the list the words are picked from. Defaults to the embedded wordlist.
+	Wordlist []string
+	// Generator is the source of randomness. Defaults to crypto/rand.
+	Generator *Generator
+}
+
+// Generate returns a new random passphrase.
+func (pg PassphraseGenerator) Generate() (string, error) {
+	words, err := pg.wordlist()
+	if err != nil {
+		return "", err
+	}
+
+	picked := make([]string, pg.words())
+	for i := range picked {
+		word, err := choice(pg.Generator, words)
+		if err != nil {
+			return "", err
+		}
+		if pg.Capitalize {
+			r := []rune(word)
+			r[0] = unicode.ToUpper(r[0])
+			word = string(r)
+		}
+		picked[i] = word
+	}
+
+	separator := pg.Separator
+	if separator == "" {
+		separator = "-"
+	}
+	return strings.Join(picked, separator), nil
+}
+
+// Entropy returns the number of random bits in a passphrase.
+func (pg PassphraseGenerator) Entropy() float64 {
+	words, err := pg.wordlist()
+	if err != nil {
+		return 0
+	}
+	return float64(pg.words()) * math.Log2(float64(len(words)))
+}
+
+func (pg PassphraseGenerator) words() int {
+	if pg.Words <= 0 {
+		return 6
+	}
+	return pg.Words
+}
+
+func (pg PassphraseGenerator) wordlist() ([]string, error) {
+	if pg.Wordlist == nil {
+		return wordlist, nil
+	}
+
+	seen := make(map[string]bool, len(pg.Wordlist))
+	words := make([]string, 0, len(pg.Wordlist))
+	for _, word := range pg.Wordlist {
+		if word != "" && !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	if len(words) < 2 {
+		return nil, ErrInvalidWordlist
+	}
+	return words, nil
+}
+
+// PasswordGenerator generates random passwords following a policy.
+// The zero value generates 16 characters long passwords using all character classes.
+type PasswordGenerator struct {
+	// Length is the number of characters of a password. Defaults to 16.
+	Length int
+	// MinUpper, MinLower, MinDigits and MinSymbols are the minimum number of characters of each class.
+	MinUpper, MinLower, MinDigits, MinSymbols int
+	// NoUpper, NoLower, NoDigits and NoSymbols exclude a character class.
+	NoUpper, NoLower, NoDigits, NoSymbols bool
+	// ExcludeAmbiguous removes characters which are easily confused, see PasswordAmbiguous.
+	ExcludeAmbiguous bool
+	// NoRepeats prevents any character from occurring more than once.
+	NoRepeats bool
+	// Generator is the source of randomness. Defaults to crypto/rand.
+	Generator *Generator
+}
+
+// passwordClass is a character class of a password with its minimum number of occurrences.
+type passwordClass struct {
+	chars []rune
+	min   int
+}
+
+// Generate returns a new random password.
+func (pg PasswordGenerator) Generate() (string, error) {
+	classes, err := pg.classes()
+	if err != nil {
+		return "", err
+	}
+
+	// Pick the required characters of every class, then fill up from all classes.
+	password := make([]rune, 0, pg.length())
+	var all []rune
+	for _, class := range classes {
+		for i := 0; i < class.min; i++ {
+			r, err := pg.pick(&class.chars)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, r)
+		}
+		all = append(all, class.chars...)
+	}
+	for len(password) < pg.length() {
+		r, err := pg.pick(&all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, r)
+	}
+
+	// Shuffle so the required characters are not at the front.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := pg.Generator.Uint64n(uint64(i + 1))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// Entropy returns an estimate of the number of random bits in a password.
+// The positions of the required characters are not taken into account.
+func (pg PasswordGenerator) Entropy() float64 {
+	classes, err := pg.classes()
+	if err != nil {
+		return 0
+	}
+
+	var bits float64
+	var all int
+	required := 0
+	for _, class := range classes {
+		for i := 0; i < class.min; i++ {
+			n := len(class.chars)
+			if pg.NoRepeats {
+				n -= i
+			}
+			bits += math.Log2(float64(n))
+		}
+		all += len(class.chars)
+		required += class.min
+	}
+	for i := required; i < pg.length(); i++ {
+		n := all
+		if pg.NoRepeats {
+			n -= i
+		}
+		bits += math.Log2(float64(n))
+	}
+	return bits
+}
+
+func (pg PasswordGenerator) length() int {
+	if pg.Length <= 0 {
+		return 16
+	}
+	return pg.Length
+}
+
+// pick returns a random character of chars, removing it if repeats are not allowed.
+func (pg PasswordGenerator) pick(chars *[]rune) (rune, error) {
+	i, err := pg.Generator.Uint64n(uint64(len(*chars)))
+	if err != nil {
+		return 0, err
+	}
+	r := (*chars)[i]
+	if pg.NoRepeats {
+		*chars = append((*chars)[:i], (*chars)[i+1:]...)
+	}
+	return r, nil
+}
+
+// classes returns the enabled character classes and validates the policy.
+func (pg PasswordGenerator) classes() ([]passwordClass, error) {
+	candidates := []struct {
+		chars    string
+		min      int
+		disabled bool
+	}{
+		{PasswordUpper, pg.MinUpper, pg.NoUpper},
+		{PasswordLower, pg.MinLower, pg.NoLower},
+		{PasswordDigits, pg.MinDigits, pg.NoDigits},
+		{PasswordSymbols, pg.MinSymbols, pg.NoSymbols},
+	}
+
+	var classes []passwordClass
+	var required, available int
+	for _, c := range candidates {
+		if c.min < 0 || (c.disabled && c.min > 0) {
+			return nil, ErrInvalidPolicy
+		}
+		if c.disabled {
+			continue
+		}
+
+		var chars []rune
+		for _, r := range c.chars {
+			if !pg.ExcludeAmbiguous || !strings.ContainsRune(PasswordAmbiguous, r) {
+				chars = append(chars, r)
+			}
+		}
+		if pg.NoRepeats && c.min > len(chars) {
+			return nil, ErrInvalidPolicy
+		}
+		classes = append(classes, passwordClass{chars: chars, min: c.min})
+		required += c.min
+		available += len(chars)
+	}
+
+	if len(classes) == 0 || required > pg.length() || (pg.NoRepeats && pg.length() > available) {
+		return nil, ErrInvalidPolicy
+	}
+	return classes, nil
+}
@@ -0,0 +1,209 @@
+package goutil
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestWordlist(t *testing.T) {
+	words := Wordlist()
+	if len(words) != 6*6*6*6 {
+		t.Fatalf("len(Wordlist()) = %v, want %v", len(words), 6*6*6*6)
+	}
+
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if seen[word] {
+			t.Errorf("Wordlist() contains %s twice", word)
+		}
+		seen[word] = true
+	}
+}
+
+func TestPassphraseGenerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator PassphraseGenerator
+		separator string
+		words     int
+	}{
+		{
+			name:      "Default",
+			generator: PassphraseGenerator{},
+			separator: "-",
+			words:     6,
+		},
+		{
+			name:      "Custom separator and length",
+			generator: PassphraseGenerator{Words: 4, Separator: " "},
+			separator: " ",
+			words:     4,
+		},
+		{
+			name:      "Capitalized",
+			generator: PassphraseGenerator{Capitalize: true},
+			separator: "-",
+			words:     6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.generator.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			words := strings.Split(got, test.separator)
+			if len(words) != test.words {
+				t.Fatalf("Generate() = %s, want %v words", got, test.words)
+			}
+			for _, word := range words {
+				if test.generator.Capitalize != unicode.IsUpper([]rune(word)[0]) {
+					t.Errorf("Generate() = %s, want capitalized %v", got, test.generator.Capitalize)
+				}
+			}
+		})
+	}
+
+	t.Run("Custom wordlist", func(t *testing.T) {
+		pg := PassphraseGenerator{Words: 3, Wordlist: []string{"yes", "no", "no"}}
+		got, err := pg.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, word := range strings.Split(got, "-") {
+			if word != "yes" && word != "no" {
+				t.Errorf("Generate() = %s, want words of the wordlist", got)
+			}
+		}
+		if entropy := pg.Entropy(); entropy != 3 {
+			t.Errorf("Entropy() = %v, want 3", entropy)
+		}
+	})
+
+	t.Run("Invalid wordlist", func(t *testing.T) {
+		pg := PassphraseGenerator{Wordlist: []string{"one", "one"}}
+		if _, err := pg.Generate(); err != ErrInvalidWordlist {
+			t.Errorf("Generate() error = %v, want %v", err, ErrInvalidWordlist)
+		}
+	})
+}
+
+func TestPassphraseGeneratorEntropy(t *testing.T) {
+	got := PassphraseGenerator{}.Entropy()
+	want := 6 * math.Log2(1296)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() = %v, want %v", got, want)
+	}
+}
+
+func TestPasswordGenerator(t *testing.T) {
+	count := func(s, chars string) int {
+		var n int
+		for _, r := range s {
+			if strings.ContainsRune(chars, r) {
+				n++
+			}
+		}
+		return n
+	}
+
+	tests := []struct {
+		name      string
+		generator PasswordGenerator
+		length    int
+	}{
+		{
+			name:      "Default",
+			generator: PasswordGenerator{},
+			length:    16,
+		},
+		{
+			name:      "Minimum classes",
+			generator: PasswordGenerator{Length: 12, MinUpper: 3, MinDigits: 3, MinSymbols: 3},
+			length:    12,
+		},
+		{
+			name:      "No ambiguous characters and no repeats",
+			generator: PasswordGenerator{Length: 40, ExcludeAmbiguous: true, NoRepeats: true},
+			length:    40,
+		},
+		{
+			name:      "Digits only",
+			generator: PasswordGenerator{Length: 10, NoUpper: true, NoLower: true, NoSymbols: true, NoRepeats: true},
+			length:    10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got, err := test.generator.Generate()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != test.length {
+					t.Fatalf("Generate() = %s, want length %v", got, test.length)
+				}
+				g := test.generator
+				if count(got, PasswordUpper) < g.MinUpper || count(got, PasswordDigits) < g.MinDigits || count(got, PasswordSymbols) < g.MinSymbols {
+					t.Fatalf("Generate() = %s, violates minimum character classes", got)
+				}
+				if g.ExcludeAmbiguous && count(got, PasswordAmbiguous) > 0 {
+					t.Fatalf("Generate() = %s, contains ambiguous characters", got)
+				}
+				if g.NoUpper && count(got, PasswordUpper) > 0 || g.NoLower && count(got, PasswordLower) > 0 || g.NoSymbols && count(got, PasswordSymbols) > 0 {
+					t.Fatalf("Generate() = %s, contains excluded character classes", got)
+				}
+				if g.NoRepeats {
+					seen := make(map[rune]bool)
+					for _, r := range got {
+						if seen[r] {
+							t.Fatalf("Generate() = %s, contains %c twice", got, r)
+						}
+						seen[r] = true
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPasswordGeneratorInvalidPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator PasswordGenerator
+	}{
+		{"Too many required", PasswordGenerator{Length: 4, MinUpper: 3, MinDigits: 3}},
+		{"Required excluded class", PasswordGenerator{NoDigits: true, MinDigits: 1}},
+		{"No classes", PasswordGenerator{NoUpper: true, NoLower: true, NoDigits: true, NoSymbols: true}},
+		{"Too long without repeats", PasswordGenerator{Length: 11, NoUpper: true, NoLower: true, NoSymbols: true, NoRepeats: true}},
+		{"Negative minimum", PasswordGenerator{MinUpper: -1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.generator.Generate(); err != ErrInvalidPolicy {
+				t.Errorf("Generate() error = %v, want %v", err, ErrInvalidPolicy)
+			}
+			if got := test.generator.Entropy(); got != 0 {
+				t.Errorf("Entropy() = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func TestPasswordGeneratorEntropy(t *testing.T) {
+	pg := PasswordGenerator{Length: 8, NoUpper: true, NoLower: true, NoSymbols: true}
+	if got, want := pg.Entropy(), 8*math.Log2(10); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() = %v, want %v", got, want)
+	}
+
+	pg.NoRepeats = true
+	want := math.Log2(10 * 9 * 8 * 7 * 6 * 5 * 4 * 3)
+	if got := pg.Entropy(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() = %v, want %v", got, want)
+	}
+}
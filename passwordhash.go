@@ -0,0 +1,459 @@
+package goutil
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrInvalidHash is returned when an encoded password hash is malformed or uses unsupported parameters.
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// PasswordHasher hashes passwords into PHC strings, e.g. "$pbkdf2-sha256$i=600000$salt$hash".
+// Salt and hash are encoded in unpadded standard Base64.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the password with a random salt.
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash, using the parameters stored in it.
+	// An error is returned if the hash is malformed or was produced by another algorithm.
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash reports whether the encoded hash was produced by another algorithm
+	// or with weaker parameters than the hasher's, so the password should be hashed again after login.
+	NeedsRehash(encoded string) bool
+}
+
+// Default password hashing parameters, as recommended by OWASP.
+const (
+	defaultPBKDF2Iterations = 600000
+	defaultScryptLogN       = 17
+	defaultScryptR          = 8
+	defaultScryptP          = 1
+	defaultArgon2Memory     = 19456
+	defaultArgon2Time       = 2
+	defaultArgon2Threads    = 1
+	defaultSaltLength       = 16
+	defaultHashLength       = 32
+)
+
+// Default limits on the parameters of hashes to verify, so a malformed or malicious hash
+// cannot make Verify allocate huge amounts of memory or run for hours.
+const (
+	defaultMaxPBKDF2Iterations = 10000000
+	defaultMaxScryptLogN       = 20
+	defaultMaxScryptR          = 16
+	defaultMaxScryptP          = 16
+	defaultMaxArgon2Memory     = 1 << 20
+	defaultMaxArgon2Time       = 16
+	defaultMaxArgon2Threads    = 16
+)
+
+// HashPassword hashes the password with the default parameters of Argon2idHasher.
+func HashPassword(password string) (string, error) {
+	return Argon2idHasher{}.Hash(password)
+}
+
+// VerifyPassword reports whether the password matches a hash produced by any of
+// PBKDF2Hasher, ScryptHasher or Argon2idHasher.
+func VerifyPassword(password, encoded string) (bool, error) {
+	phc, err := parsePHC(encoded)
+	if err != nil {
+		return false, err
+	}
+	switch phc.id {
+	case pbkdf2ID:
+		return PBKDF2Hasher{}.Verify(password, encoded)
+	case scryptID:
+		return ScryptHasher{}.Verify(password, encoded)
+	case argon2idID:
+		return Argon2idHasher{}.Verify(password, encoded)
+	default:
+		return false, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidHash, phc.id)
+	}
+}
+
+const (
+	pbkdf2ID   = "pbkdf2-sha256"
+	scryptID   = "scrypt"
+	argon2idID = "argon2id"
+)
+
+// PBKDF2Hasher hashes passwords with PBKDF2-HMAC-SHA256.
+// The zero value uses 600000 iterations, a 16 bytes salt and a 32 bytes hash.
+type PBKDF2Hasher struct {
+	// Iterations is the number of iterations. Defaults to 600000.
+	Iterations int
+	// SaltLength is the length of the salt in bytes. Defaults to 16.
+	SaltLength int
+	// KeyLength is the length of the hash in bytes. Defaults to 32.
+	KeyLength int
+	// MaxIterations is the highest number of iterations of a hash Verify accepts.
+	// Defaults to 10000000; it must not be lower than Iterations.
+	MaxIterations int
+	// Generator is the source of the salt. Defaults to crypto/rand.
+	Generator *Generator
+}
+
+// Hash returns the encoded hash of the password with a random salt.
+func (h PBKDF2Hasher) Hash(password string) (string, error) {
+	iterations := positiveOr(h.Iterations, defaultPBKDF2Iterations)
+	if iterations > h.maxIterations() {
+		return "", fmt.Errorf("%w: iterations exceed MaxIterations", ErrInvalidHash)
+	}
+	salt, err := newSalt(h.Generator, h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key := pbkdf2.Key([]byte(password), salt, iterations, positiveOr(h.KeyLength, defaultHashLength), sha256.New)
+	return formatPHC(pbkdf2ID, fmt.Sprintf("i=%d", iterations), salt, key), nil
+}
+
+// Verify reports whether the password matches the encoded hash.
+func (h PBKDF2Hasher) Verify(password, encoded string) (bool, error) {
+	phc, err := parsePHCFor(encoded, pbkdf2ID)
+	if err != nil {
+		return false, err
+	}
+	iterations, err := phc.param("i", 1, int64(h.maxIterations()))
+	if err != nil {
+		return false, err
+	}
+	key := pbkdf2.Key([]byte(password), phc.salt, int(iterations), len(phc.hash), sha256.New)
+	return Secret(key).Equal(phc.hash), nil
+}
+
+// NeedsRehash reports whether the encoded hash is not a PBKDF2 hash or weaker than configured.
+func (h PBKDF2Hasher) NeedsRehash(encoded string) bool {
+	phc, err := parsePHCFor(encoded, pbkdf2ID)
+	if err != nil {
+		return true
+	}
+	iterations, err := phc.param("i", 1, int64(h.maxIterations()))
+	return err != nil ||
+		iterations < int64(positiveOr(h.Iterations, defaultPBKDF2Iterations)) ||
+		phc.weaker(h.SaltLength, h.KeyLength)
+}
+
+func (h PBKDF2Hasher) maxIterations() int {
+	return positiveOr(h.MaxIterations, defaultMaxPBKDF2Iterations)
+}
+
+// ScryptHasher hashes passwords with scrypt.
+// The zero value uses N = 2^17, r = 8, p = 1, a 16 bytes salt and a 32 bytes hash.
+type ScryptHasher struct {
+	// LogN is the binary logarithm of the CPU/memory cost N. Defaults to 17.
+	LogN int
+	// R is the block size. Defaults to 8.
+	R int
+	// P is the parallelization. Defaults to 1.
+	P int
+	// SaltLength is the length of the salt in bytes. Defaults to 16.
+	SaltLength int
+	// KeyLength is the length of the hash in bytes. Defaults to 32.
+	KeyLength int
+	// MaxLogN, MaxR and MaxP are the highest parameters of a hash Verify accepts.
+	// They default to 20, 16 and 16 and must not be lower than LogN, R and P.
+	MaxLogN, MaxR, MaxP int
+	// Generator is the source of the salt. Defaults to crypto/rand.
+	Generator *Generator
+}
+
+// Hash returns the encoded hash of the password with a random salt.
+func (h ScryptHasher) Hash(password string) (string, error) {
+	logN, r, p := h.params()
+	if maxLogN, maxR, maxP := h.limits(); logN > maxLogN || r > maxR || p > maxP {
+		return "", fmt.Errorf("%w: parameters exceed MaxLogN, MaxR or MaxP", ErrInvalidHash)
+	}
+	salt, err := newSalt(h.Generator, h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(password), salt, 1<<logN, r, p, positiveOr(h.KeyLength, defaultHashLength))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	return formatPHC(scryptID, fmt.Sprintf("ln=%d,r=%d,p=%d", logN, r, p), salt, key), nil
+}
+
+// Verify reports whether the password matches the encoded hash.
+func (h ScryptHasher) Verify(password, encoded string) (bool, error) {
+	phc, logN, r, p, err := h.parse(encoded)
+	if err != nil {
+		return false, err
+	}
+	key, err := scrypt.Key([]byte(password), phc.salt, 1<<logN, r, p, len(phc.hash))
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	return Secret(key).Equal(phc.hash), nil
+}
+
+// NeedsRehash reports whether the encoded hash is not a scrypt hash or weaker than configured.
+func (h ScryptHasher) NeedsRehash(encoded string) bool {
+	phc, logN, r, p, err := h.parse(encoded)
+	if err != nil {
+		return true
+	}
+	wantLogN, wantR, wantP := h.params()
+	return logN < wantLogN || r < wantR || p < wantP || phc.weaker(h.SaltLength, h.KeyLength)
+}
+
+func (h ScryptHasher) params() (logN, r, p int) {
+	return positiveOr(h.LogN, defaultScryptLogN), positiveOr(h.R, defaultScryptR), positiveOr(h.P, defaultScryptP)
+}
+
+func (h ScryptHasher) limits() (maxLogN, maxR, maxP int) {
+	return positiveOr(h.MaxLogN, defaultMaxScryptLogN), positiveOr(h.MaxR, defaultMaxScryptR), positiveOr(h.MaxP, defaultMaxScryptP)
+}
+
+func (h ScryptHasher) parse(encoded string) (phc phcString, logN, r, p int, err error) {
+	if phc, err = parsePHCFor(encoded, scryptID); err != nil {
+		return
+	}
+	maxLogN, maxR, maxP := h.limits()
+	var n [3]int64
+	for i, param := range []struct {
+		name string
+		max  int
+	}{{"ln", maxLogN}, {"r", maxR}, {"p", maxP}} {
+		if n[i], err = phc.param(param.name, 1, int64(param.max)); err != nil {
+			return
+		}
+	}
+	return phc, int(n[0]), int(n[1]), int(n[2]), nil
+}
+
+// Argon2idHasher hashes passwords with Argon2id.
+// The zero value uses 19 MiB of memory, 2 iterations, 1 thread, a 16 bytes salt and a 32 bytes hash.
+type Argon2idHasher struct {
+	// Memory is the memory cost in KiB. Defaults to 19456.
+	Memory uint32
+	// Time is the number of iterations. Defaults to 2.
+	Time uint32
+	// Threads is the degree of parallelism. Defaults to 1.
+	Threads uint8
+	// SaltLength is the length of the salt in bytes. Defaults to 16.
+	SaltLength int
+	// KeyLength is the length of the hash in bytes. Defaults to 32.
+	KeyLength int
+	// MaxMemory, MaxTime and MaxThreads are the highest parameters of a hash Verify accepts.
+	// They default to 1 GiB, 16 and 16 and must not be lower than Memory, Time and Threads.
+	MaxMemory, MaxTime uint32
+	MaxThreads         uint8
+	// Generator is the source of the salt. Defaults to crypto/rand.
+	Generator *Generator
+}
+
+// Hash returns the encoded hash of the password with a random salt.
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	memory, time, threads := h.params()
+	if maxMemory, maxTime, maxThreads := h.limits(); memory > maxMemory || time > maxTime || threads > maxThreads {
+		return "", fmt.Errorf("%w: parameters exceed MaxMemory, MaxTime or MaxThreads", ErrInvalidHash)
+	}
+	salt, err := newSalt(h.Generator, h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key, err := argon2idKey([]byte(password), salt, time, memory, threads, positiveOr(h.KeyLength, defaultHashLength))
+	if err != nil {
+		return "", err
+	}
+	params := fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2.Version, memory, time, threads)
+	return formatPHC(argon2idID, params, salt, key), nil
+}
+
+// Verify reports whether the password matches the encoded hash.
+func (h Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	phc, memory, time, threads, err := h.parse(encoded)
+	if err != nil {
+		return false, err
+	}
+	key, err := argon2idKey([]byte(password), phc.salt, time, memory, threads, len(phc.hash))
+	if err != nil {
+		return false, err
+	}
+	return Secret(key).Equal(phc.hash), nil
+}
+
+// NeedsRehash reports whether the encoded hash is not an Argon2id hash or weaker than configured.
+func (h Argon2idHasher) NeedsRehash(encoded string) bool {
+	phc, memory, time, _, err := h.parse(encoded)
+	if err != nil {
+		return true
+	}
+	wantMemory, wantTime, _ := h.params()
+	return memory < wantMemory || time < wantTime || phc.weaker(h.SaltLength, h.KeyLength)
+}
+
+func (h Argon2idHasher) params() (memory, time uint32, threads uint8) {
+	memory, time, threads = h.Memory, h.Time, h.Threads
+	if memory == 0 {
+		memory = defaultArgon2Memory
+	}
+	if time == 0 {
+		time = defaultArgon2Time
+	}
+	if threads == 0 {
+		threads = defaultArgon2Threads
+	}
+	return memory, time, threads
+}
+
+// argon2idKey derives a key with Argon2id. Unlike argon2.IDKey, it rejects parameters it would
+// otherwise adjust or panic on, so the parameters written to a hash are the ones actually used.
+func argon2idKey(password, salt []byte, time, memory uint32, threads uint8, keyLen int) ([]byte, error) {
+	switch {
+	case threads < 1:
+		return nil, fmt.Errorf("%w: at least one thread is required", ErrInvalidHash)
+	case time < 1:
+		return nil, fmt.Errorf("%w: at least one iteration is required", ErrInvalidHash)
+	case memory < 8*uint32(threads):
+		return nil, fmt.Errorf("%w: memory must be at least 8 KiB per thread", ErrInvalidHash)
+	case keyLen < 1 || uint64(keyLen) > 1<<32-1:
+		return nil, fmt.Errorf("%w: invalid key length %d", ErrInvalidHash, keyLen)
+	}
+	return argon2.IDKey(password, salt, time, memory, threads, uint32(keyLen)), nil
+}
+
+func (h Argon2idHasher) limits() (maxMemory, maxTime uint32, maxThreads uint8) {
+	maxMemory, maxTime, maxThreads = h.MaxMemory, h.MaxTime, h.MaxThreads
+	if maxMemory == 0 {
+		maxMemory = defaultMaxArgon2Memory
+	}
+	if maxTime == 0 {
+		maxTime = defaultMaxArgon2Time
+	}
+	if maxThreads == 0 {
+		maxThreads = defaultMaxArgon2Threads
+	}
+	return maxMemory, maxTime, maxThreads
+}
+
+func (h Argon2idHasher) parse(encoded string) (phc phcString, memory, time uint32, threads uint8, err error) {
+	if phc, err = parsePHCFor(encoded, argon2idID); err != nil {
+		return
+	}
+	if phc.version != argon2.Version {
+		err = fmt.Errorf("%w: unsupported version %d", ErrInvalidHash, phc.version)
+		return
+	}
+	maxMemory, maxTime, maxThreads := h.limits()
+	p, err := phc.param("p", 1, int64(maxThreads))
+	if err != nil {
+		return
+	}
+	m, err := phc.param("m", 8*p, int64(maxMemory))
+	if err != nil {
+		return
+	}
+	t, err := phc.param("t", 1, int64(maxTime))
+	if err != nil {
+		return
+	}
+	return phc, uint32(m), uint32(t), uint8(p), nil
+}
+
+// phcString is a parsed password hash in the PHC string format.
+type phcString struct {
+	id      string
+	version int
+	params  map[string]string
+	salt    []byte
+	hash    []byte
+}
+
+func formatPHC(id, params string, salt, hash []byte) string {
+	return "$" + id + "$" + params + "$" + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(hash)
+}
+
+// parsePHC parses an encoded hash of the form $id[$v=version][$params]$salt$hash.
+func parsePHC(encoded string) (phcString, error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) < 4 || fields[0] != "" || fields[1] == "" {
+		return phcString{}, ErrInvalidHash
+	}
+
+	phc := phcString{id: fields[1], params: make(map[string]string)}
+	fields = fields[2:]
+	if strings.HasPrefix(fields[0], "v=") {
+		v, err := strconv.Atoi(strings.TrimPrefix(fields[0], "v="))
+		if err != nil {
+			return phcString{}, fmt.Errorf("%w: invalid version", ErrInvalidHash)
+		}
+		phc.version = v
+		fields = fields[1:]
+	}
+	if len(fields) == 3 {
+		for _, param := range strings.Split(fields[0], ",") {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok || name == "" {
+				return phcString{}, fmt.Errorf("%w: invalid parameter %q", ErrInvalidHash, param)
+			}
+			phc.params[name] = value
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 2 {
+		return phcString{}, ErrInvalidHash
+	}
+
+	var err error
+	if phc.salt, err = base64.RawStdEncoding.DecodeString(fields[0]); err != nil {
+		return phcString{}, fmt.Errorf("%w: invalid salt", ErrInvalidHash)
+	}
+	if phc.hash, err = base64.RawStdEncoding.DecodeString(fields[1]); err != nil || len(phc.hash) == 0 {
+		return phcString{}, fmt.Errorf("%w: invalid hash", ErrInvalidHash)
+	}
+	return phc, nil
+}
+
+// parsePHCFor is like parsePHC, but fails if the hash was produced by another algorithm.
+func parsePHCFor(encoded, id string) (phcString, error) {
+	phc, err := parsePHC(encoded)
+	if err != nil {
+		return phcString{}, err
+	}
+	if phc.id != id {
+		return phcString{}, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidHash, phc.id)
+	}
+	return phc, nil
+}
+
+// param returns the integer parameter with the given name, which must lie in [min, max].
+func (phc phcString) param(name string, min, max int64) (int64, error) {
+	value, ok := phc.params[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: missing parameter %s", ErrInvalidHash, name)
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%w: invalid parameter %s", ErrInvalidHash, name)
+	}
+	return n, nil
+}
+
+// weaker reports whether salt or hash are shorter than configured.
+func (phc phcString) weaker(saltLength, keyLength int) bool {
+	return len(phc.salt) < positiveOr(saltLength, defaultSaltLength) || len(phc.hash) < positiveOr(keyLength, defaultHashLength)
+}
+
+func newSalt(g *Generator, length int) ([]byte, error) {
+	salt := make([]byte, positiveOr(length, defaultSaltLength))
+	if _, err := g.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func positiveOr(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
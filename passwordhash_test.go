@@ -0,0 +1,243 @@
+package goutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordHashers(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		prefix string
+	}{
+		{"pbkdf2", PBKDF2Hasher{Iterations: 1000}, "$pbkdf2-sha256$i=1000$"},
+		{"scrypt", ScryptHasher{LogN: 10, R: 8, P: 1}, "$scrypt$ln=10,r=8,p=1$"},
+		{"argon2id", Argon2idHasher{Memory: 64, Time: 1, Threads: 2}, "$argon2id$v=19$m=64,t=1,p=2$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.hasher.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Errorf("Hash() = %q, want prefix %q", encoded, tt.prefix)
+			}
+
+			if ok, err := tt.hasher.Verify("correct horse battery staple", encoded); !ok || err != nil {
+				t.Errorf("Verify() with correct password = %v, %v", ok, err)
+			}
+			if ok, err := tt.hasher.Verify("Correct horse battery staple", encoded); ok || err != nil {
+				t.Errorf("Verify() with wrong password = %v, %v", ok, err)
+			}
+			if ok, err := VerifyPassword("correct horse battery staple", encoded); !ok || err != nil {
+				t.Errorf("VerifyPassword() = %v, %v", ok, err)
+			}
+			if tt.hasher.NeedsRehash(encoded) {
+				t.Error("NeedsRehash() of fresh hash = true")
+			}
+
+			other, err := tt.hasher.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if other == encoded {
+				t.Error("Hash() returned the same hash twice")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherVectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasher   PasswordHasher
+		password string
+		encoded  string
+	}{
+		// PBKDF2-HMAC-SHA256 of "password" with salt "salt" and one iteration.
+		{"pbkdf2", PBKDF2Hasher{}, "password", "$pbkdf2-sha256$i=1$c2FsdA$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs"},
+		// Test vectors from RFC 7914.
+		{"pbkdf2 two blocks", PBKDF2Hasher{}, "passwd", "$pbkdf2-sha256$i=1$c2FsdA$VawEblbjCJ/sFpHCJUS2BflBhSFt3gRl5oudV8INrLxJypzM8Xm2RZkWZLOdd+8xfHG4RbHjC9UJESBB06GXgw"},
+		{"scrypt empty", ScryptHasher{}, "", "$scrypt$ln=4,r=1,p=1$$d9ZXYjhleyA7GcpCwYoEl/FrSETjB0ro39/6P+3iFEL80Aad7QlI+DJqdToPyB8X6NPg+y4NNijPNeIMONGJBg"},
+		{"scrypt", ScryptHasher{}, "password", "$scrypt$ln=10,r=8,p=16$TmFDbA$/bq+HJ00cgB4VucZDQHp/nxq18vII3gw53N2Y0s3MWIurzDZLiKjiG/xCSedmDDaxyevuUqD7m2DYMvfoswGQA"},
+		// Test vector of the Argon2 reference implementation.
+		{"argon2id", Argon2idHasher{}, "password", "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok, err := tt.hasher.Verify(tt.password, tt.encoded); !ok || err != nil {
+				t.Errorf("Verify() = %v, %v", ok, err)
+			}
+			if ok, err := tt.hasher.Verify(tt.password+"x", tt.encoded); ok || err != nil {
+				t.Errorf("Verify() with wrong password = %v, %v", ok, err)
+			}
+		})
+	}
+
+	encoded := tests[0].encoded
+	h := PBKDF2Hasher{Iterations: 1, SaltLength: 4, Generator: NewGenerator(strings.NewReader("salt"))}
+	if got, err := h.Hash("password"); got != encoded || err != nil {
+		t.Errorf("Hash() = %q, %v, want %q", got, err, encoded)
+	}
+}
+
+func TestArgon2idHasherMemoryPerThread(t *testing.T) {
+	if _, err := (Argon2idHasher{Memory: 16, Threads: 4}).Hash("password"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Hash() with 4 KiB per thread error = %v, want %v", err, ErrInvalidHash)
+	}
+
+	h := Argon2idHasher{Memory: 32, Time: 1, Threads: 4}
+	encoded, err := h.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=32,t=1,p=4$") {
+		t.Errorf("Hash() = %q, want the configured parameters", encoded)
+	}
+	if ok, err := h.Verify("password", encoded); !ok || err != nil {
+		t.Errorf("Verify() = %v, %v", ok, err)
+	}
+}
+
+func TestPasswordHasherLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+	}{
+		{"PBKDF2", PBKDF2Hasher{Iterations: 1000, MaxIterations: 999}},
+		{"scrypt", ScryptHasher{LogN: 10, MaxLogN: 9}},
+		{"Argon2id", Argon2idHasher{Memory: 64, Time: 2, MaxTime: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.hasher.Hash("password"); !errors.Is(err, ErrInvalidHash) {
+				t.Errorf("Hash() above the limits error = %v, want %v", err, ErrInvalidHash)
+			}
+		})
+	}
+
+	encoded, err := Argon2idHasher{Memory: 64, Time: 2}.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := (Argon2idHasher{MaxTime: 1}).Verify("password", encoded); ok || !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Verify() above MaxTime = %v, %v, want ErrInvalidHash", ok, err)
+	}
+	if ok, err := (Argon2idHasher{MaxTime: 2}).Verify("password", encoded); !ok || err != nil {
+		t.Errorf("Verify() within MaxTime = %v, %v, want true", ok, err)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	weak, err := PBKDF2Hasher{Iterations: 1000, SaltLength: 8}.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrypt, err := ScryptHasher{LogN: 10}.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon, err := Argon2idHasher{Memory: 64, Time: 1}.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		hasher  PasswordHasher
+		encoded string
+		want    bool
+	}{
+		{"same parameters", PBKDF2Hasher{Iterations: 1000, SaltLength: 8}, weak, false},
+		{"more iterations", PBKDF2Hasher{Iterations: 2000, SaltLength: 8}, weak, true},
+		{"fewer iterations", PBKDF2Hasher{Iterations: 500, SaltLength: 8}, weak, false},
+		{"longer salt", PBKDF2Hasher{Iterations: 1000}, weak, true},
+		{"longer key", PBKDF2Hasher{Iterations: 1000, SaltLength: 8, KeyLength: 64}, weak, true},
+		{"scrypt default", ScryptHasher{}, scrypt, true},
+		{"scrypt weaker", ScryptHasher{LogN: 9}, scrypt, false},
+		{"scrypt higher r", ScryptHasher{LogN: 10, R: 16}, scrypt, true},
+		{"argon2id more memory", Argon2idHasher{Memory: 128, Time: 1}, argon, true},
+		{"argon2id more time", Argon2idHasher{Memory: 64, Time: 2}, argon, true},
+		{"argon2id more threads", Argon2idHasher{Memory: 64, Time: 1, Threads: 4}, argon, false},
+		{"other algorithm", Argon2idHasher{Memory: 64, Time: 1}, weak, true},
+		{"malformed", PBKDF2Hasher{}, "$pbkdf2-sha256$i=x$c2FsdA$c2FsdA", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.encoded); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"no dollar", "pbkdf2-sha256$i=1$c2FsdA$c2FsdA"},
+		{"unknown algorithm", "$bcrypt$i=1$c2FsdA$c2FsdA"},
+		{"missing hash", "$pbkdf2-sha256$i=1$c2FsdA"},
+		{"empty hash", "$pbkdf2-sha256$i=1$c2FsdA$"},
+		{"invalid base64", "$pbkdf2-sha256$i=1$c2F*dA$c2FsdA"},
+		{"missing parameter", "$pbkdf2-sha256$n=1$c2FsdA$c2FsdA"},
+		{"zero iterations", "$pbkdf2-sha256$i=0$c2FsdA$c2FsdA"},
+		{"invalid parameter", "$scrypt$ln,r=8,p=1$c2FsdA$c2FsdA"},
+		{"scrypt cost too high", "$scrypt$ln=63,r=8,p=1$c2FsdA$c2FsdA"},
+		{"scrypt cost above limit", "$scrypt$ln=21,r=8,p=1$c2FsdA$c2FsdA"},
+		{"scrypt block size above limit", "$scrypt$ln=10,r=1024,p=1$c2FsdA$c2FsdA"},
+		{"scrypt parallelism above limit", "$scrypt$ln=10,r=8,p=1000000$c2FsdA$c2FsdA"},
+		{"pbkdf2 iterations above limit", "$pbkdf2-sha256$i=2000000000$c2FsdA$c2FsdA"},
+		{"argon2 version", "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$c2FsdA"},
+		{"argon2 missing version", "$argon2id$m=64,t=1,p=1$c2FsdA$c2FsdA"},
+		{"argon2 too little memory", "$argon2id$v=19$m=8,t=1,p=2$c2FsdA$c2FsdA"},
+		{"argon2 memory above limit", "$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$c2FsdA"},
+		{"argon2 time above limit", "$argon2id$v=19$m=64,t=4294967295,p=1$c2FsdA$c2FsdA"},
+		{"argon2 threads above limit", "$argon2id$v=19$m=2048,t=1,p=255$c2FsdA$c2FsdA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := VerifyPassword("password", tt.encoded)
+			if ok || !errors.Is(err, ErrInvalidHash) {
+				t.Errorf("VerifyPassword() = %v, %v, want ErrInvalidHash", ok, err)
+			}
+		})
+	}
+
+	if _, err := (ScryptHasher{}).Verify("password", "$pbkdf2-sha256$i=1$c2FsdA$c2FsdA"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Verify() of other algorithm = %v, want ErrInvalidHash", err)
+	}
+}
+
+func TestPasswordHasherSaltError(t *testing.T) {
+	g := NewGenerator(failingReader{})
+	for _, h := range []PasswordHasher{PBKDF2Hasher{Generator: g}, ScryptHasher{Generator: g}, Argon2idHasher{Generator: g}} {
+		if _, err := h.Hash("password"); err == nil {
+			t.Errorf("%T.Hash() without entropy succeeded", h)
+		}
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	if testing.Short() {
+		t.Skip("hashing with default parameters is slow")
+	}
+	encoded, err := HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("HashPassword() = %q", encoded)
+	}
+	if ok, err := VerifyPassword("password", encoded); !ok || err != nil {
+		t.Errorf("VerifyPassword() = %v, %v", ok, err)
+	}
+	if (Argon2idHasher{}).NeedsRehash(encoded) {
+		t.Error("NeedsRehash() of default hash = true")
+	}
+}
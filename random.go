@@ -0,0 +1,178 @@
+package goutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+)
+
+var (
+	// ErrInvalidRange is returned when a random number is requested from an empty range.
+	ErrInvalidRange = errors.New("min must be lower than max")
+	// ErrNonFiniteRange is returned when a random float is requested from a range that is not finite.
+	ErrNonFiniteRange = errors.New("range must be finite")
+	// ErrEmptySlice is returned when a random element is requested from an empty slice.
+	ErrEmptySlice = errors.New("empty slice")
+	// ErrInvalidWeights is returned when the weights of a weighted choice are unusable.
+	ErrInvalidWeights = errors.New("weights must be non-negative, match the items and not sum up to 0")
+)
+
+// Uint64 returns a random number covering the whole uint64 range.
+func (g *Generator) Uint64() (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(g.source(), b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// Uint64n returns a random number between 0 and n (exclusive).
+// Values are drawn by rejection sampling, so the result has no modulo bias.
+func (g *Generator) Uint64n(n uint64) (uint64, error) {
+	if n == 0 {
+		return 0, ErrInvalidMax
+	}
+	mask := ^uint64(0) >> bits.LeadingZeros64(n-1)
+	for {
+		v, err := g.Uint64()
+		if err != nil {
+			return 0, err
+		}
+		if v &= mask; v < n {
+			return v, nil
+		}
+	}
+}
+
+// Float64 returns a uniformly distributed random number between 0 and 1 (exclusive).
+func (g *Generator) Float64() (float64, error) {
+	v, err := g.Uint64()
+	if err != nil {
+		return 0, err
+	}
+	return float64(v>>11) / (1 << 53), nil
+}
+
+// Bool returns a random boolean.
+func (g *Generator) Bool() (bool, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(g.source(), b[:]); err != nil {
+		return false, err
+	}
+	return b[0]&1 == 1, nil
+}
+
+// SecureIntRange returns a cryptographically secure random number between min and max (exclusive).
+// Integer types are drawn without modulo bias, float types are drawn uniformly.
+func SecureIntRange[N Number](min, max N, opts ...RandOption) (N, error) {
+	return intRange(applyRandOptions(defaultGenerator, opts), min, max)
+}
+
+// SecureFloat64 returns a cryptographically secure random number between 0 and 1 (exclusive).
+func SecureFloat64(opts ...RandOption) (float64, error) {
+	return applyRandOptions(defaultGenerator, opts).Float64()
+}
+
+// SecureBool returns a cryptographically secure random boolean.
+func SecureBool(opts ...RandOption) (bool, error) {
+	return applyRandOptions(defaultGenerator, opts).Bool()
+}
+
+// SecureChoice returns a cryptographically secure random element of the slice.
+func SecureChoice[A any](items []A, opts ...RandOption) (A, error) {
+	return choice(applyRandOptions(defaultGenerator, opts), items)
+}
+
+// SecureWeightedChoice returns a cryptographically secure random element of the slice.
+// The chance of each item being picked is proportional to its weight.
+func SecureWeightedChoice[A any](items []A, weights []int, opts ...RandOption) (A, error) {
+	return weightedChoice(applyRandOptions(defaultGenerator, opts), items, weights)
+}
+
+func intRange[N Number](g *Generator, min, max N) (N, error) {
+	if !(min < max) {
+		return 0, ErrInvalidRange
+	}
+
+	switch any(min).(type) {
+	case float32, float64:
+		span := float64(max) - float64(min)
+		if math.IsInf(float64(min), 0) || math.IsInf(float64(max), 0) || math.IsInf(span, 0) {
+			return 0, ErrNonFiniteRange
+		}
+		for {
+			f, err := g.Float64()
+			if err != nil {
+				return 0, err
+			}
+			// Rounding can land on max, which is excluded.
+			if v := N(float64(min) + f*span); v < max {
+				return v, nil
+			}
+		}
+	case int, int8, int16, int32, int64:
+		span := uint64(int64(max)) - uint64(int64(min))
+		r, err := g.Uint64n(span)
+		if err != nil {
+			return 0, err
+		}
+		return N(int64(uint64(int64(min)) + r)), nil
+	default:
+		r, err := g.Uint64n(uint64(max) - uint64(min))
+		if err != nil {
+			return 0, err
+		}
+		return N(uint64(min) + r), nil
+	}
+}
+
+func choice[A any](g *Generator, items []A) (A, error) {
+	var zero A
+	if len(items) == 0 {
+		return zero, ErrEmptySlice
+	}
+	i, err := g.Uint64n(uint64(len(items)))
+	if err != nil {
+		return zero, err
+	}
+	return items[i], nil
+}
+
+func weightedChoice[A any](g *Generator, items []A, weights []int) (A, error) {
+	var zero A
+	if len(items) == 0 {
+		return zero, ErrEmptySlice
+	}
+	if len(weights) != len(items) {
+		return zero, ErrInvalidWeights
+	}
+
+	var total uint64
+	for _, w := range weights {
+		if w < 0 {
+			return zero, ErrInvalidWeights
+		}
+		var carry uint64
+		total, carry = bits.Add64(total, uint64(w), 0)
+		if carry != 0 {
+			return zero, ErrInvalidWeights
+		}
+	}
+	if total == 0 {
+		return zero, ErrInvalidWeights
+	}
+
+	r, err := g.Uint64n(total)
+	if err != nil {
+		return zero, err
+	}
+	for i, w := range weights {
+		if r < uint64(w) {
+			return items[i], nil
+		}
+		r -= uint64(w)
+	}
+	return zero, ErrInvalidWeights
+}
@@ -0,0 +1,213 @@
+package goutil
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSecureIntRange(t *testing.T) {
+	t.Run("int8 full range", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			got, err := SecureIntRange[int8](math.MinInt8, math.MaxInt8)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == math.MaxInt8 {
+				t.Fatalf("SecureIntRange(%v, %v) = %v, want below max", math.MinInt8, math.MaxInt8, got)
+			}
+		}
+	})
+
+	t.Run("negative range", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			got, err := SecureIntRange(-10, -5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got < -10 || got >= -5 {
+				t.Fatalf("SecureIntRange(-10, -5) = %v, out of range", got)
+			}
+		}
+	})
+
+	t.Run("uint64 full range", func(t *testing.T) {
+		if _, err := SecureIntRange[uint64](0, math.MaxUint64); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("float range", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			got, err := SecureIntRange(1.5, 2.5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got < 1.5 || got >= 2.5 {
+				t.Fatalf("SecureIntRange(1.5, 2.5) = %v, out of range", got)
+			}
+		}
+	})
+
+	t.Run("non-finite float ranges", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			min, max float64
+		}{
+			{"overflowing span", -math.MaxFloat64, math.MaxFloat64},
+			{"infinite min", math.Inf(-1), 0},
+			{"infinite max", 0, math.Inf(1)},
+			{"infinite bounds", math.Inf(-1), math.Inf(1)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := SecureIntRange(tt.min, tt.max); err != ErrNonFiniteRange {
+					t.Errorf("SecureIntRange(%v, %v) error = %v, want %v", tt.min, tt.max, err, ErrNonFiniteRange)
+				}
+			})
+		}
+		if _, err := SecureIntRange(math.NaN(), 1); err != ErrInvalidRange {
+			t.Errorf("SecureIntRange(NaN, 1) error = %v, want %v", err, ErrInvalidRange)
+		}
+	})
+
+	t.Run("float32 full range", func(t *testing.T) {
+		got, err := SecureIntRange[float32](-math.MaxFloat32, math.MaxFloat32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.IsInf(float64(got), 0) {
+			t.Errorf("SecureIntRange() = %v", got)
+		}
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		if _, err := SecureIntRange(5, 5); err != ErrInvalidRange {
+			t.Errorf("SecureIntRange(5, 5) error = %v, want %v", err, ErrInvalidRange)
+		}
+	})
+}
+
+func TestUint64nUnbiased(t *testing.T) {
+	// Every value of a byte stream of 0xff is rejected for n = 3 until a usable one follows.
+	source := append(bytes.Repeat([]byte{0xff}, 8), 0, 0, 0, 0, 0, 0, 0, 2)
+	got, err := NewGenerator(bytes.NewReader(source)).Uint64n(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 2 {
+		t.Errorf("Uint64n(3) = %v, want 2", got)
+	}
+
+	counts := make([]int, 6)
+	for i := 0; i < 6000; i++ {
+		v, err := defaultGenerator.Uint64n(6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[v]++
+	}
+	for v, c := range counts {
+		if c < 800 || c > 1200 {
+			t.Errorf("value %v drawn %v times out of 6000", v, c)
+		}
+	}
+}
+
+func TestSecureFloat64(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		got, err := SecureFloat64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got < 0 || got >= 1 {
+			t.Fatalf("SecureFloat64() = %v, out of range", got)
+		}
+	}
+}
+
+func TestSecureBool(t *testing.T) {
+	var trues int
+	for i := 0; i < 1000; i++ {
+		got, err := SecureBool()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got {
+			trues++
+		}
+	}
+	if trues < 400 || trues > 600 {
+		t.Errorf("SecureBool() returned true %v times out of 1000", trues)
+	}
+}
+
+func TestSecureChoice(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		got, err := SecureChoice(items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[got] = true
+	}
+	if len(seen) != len(items) {
+		t.Errorf("SecureChoice(%v) only returned %v", items, seen)
+	}
+
+	if _, err := SecureChoice([]int{}); err != ErrEmptySlice {
+		t.Errorf("SecureChoice([]) error = %v, want %v", err, ErrEmptySlice)
+	}
+}
+
+func TestSecureWeightedChoice(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []string
+		weights []int
+		wantErr error
+	}{
+		{
+			name:    "Single weighted item",
+			items:   []string{"a", "b", "c"},
+			weights: []int{0, 5, 0},
+		},
+		{
+			name:    "Length mismatch",
+			items:   []string{"a", "b"},
+			weights: []int{1},
+			wantErr: ErrInvalidWeights,
+		},
+		{
+			name:    "Negative weight",
+			items:   []string{"a", "b"},
+			weights: []int{1, -1},
+			wantErr: ErrInvalidWeights,
+		},
+		{
+			name:    "Zero weights",
+			items:   []string{"a", "b"},
+			weights: []int{0, 0},
+			wantErr: ErrInvalidWeights,
+		},
+		{
+			name:    "Empty items",
+			wantErr: ErrEmptySlice,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got, err := SecureWeightedChoice(test.items, test.weights)
+				if err != test.wantErr {
+					t.Fatalf("SecureWeightedChoice(%v, %v) error = %v, want %v", test.items, test.weights, err, test.wantErr)
+				}
+				if err == nil && got != "b" {
+					t.Fatalf("SecureWeightedChoice(%v, %v) = %v, want b", test.items, test.weights, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package goutil
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"sync"
+)
+
+// RandSource is a source of random bytes, as read by a Generator.
+// crypto/rand.Reader is the default; NewSeededSource returns a reproducible source for tests.
+type RandSource interface {
+	io.Reader
+}
+
+// RandOption selects the RandSource of a function consuming randomness,
+// e.g. SecureRandom, GenerateToken or Shuffle.
+type RandOption func(*Generator)
+
+// WithRandSource makes a function read its randomness from src.
+// A nil source selects crypto/rand.Reader.
+func WithRandSource(src RandSource) RandOption {
+	return func(g *Generator) {
+		g.Source = src
+	}
+}
+
+// WithRandContext makes a function read its randomness from the RandSource stored in ctx
+// by ContextWithRandSource. Without one, the function keeps its default source.
+func WithRandContext(ctx context.Context) RandOption {
+	return func(g *Generator) {
+		if src := RandSourceFromContext(ctx); src != nil {
+			g.Source = src
+		}
+	}
+}
+
+type randSourceKey struct{}
+
+// ContextWithRandSource returns a copy of ctx carrying src, to be picked up with WithRandContext.
+func ContextWithRandSource(ctx context.Context, src RandSource) context.Context {
+	return context.WithValue(ctx, randSourceKey{}, src)
+}
+
+// RandSourceFromContext returns the RandSource stored in ctx, or nil if there is none.
+func RandSourceFromContext(ctx context.Context) RandSource {
+	src, _ := ctx.Value(randSourceKey{}).(RandSource)
+	return src
+}
+
+// applyRandOptions returns def if there are no options, or a generator with the options applied to def.
+func applyRandOptions(def *Generator, opts []RandOption) *Generator {
+	if len(opts) == 0 {
+		return def
+	}
+	g := &Generator{Source: def.source()}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// NewSeededSource returns a deterministic RandSource producing the same bytes for the same seed,
+// regardless of how they are read. It is not cryptographically secure and meant for reproducible tests.
+// It is safe for concurrent use.
+func NewSeededSource(seed uint64) RandSource {
+	return &seededSource{state: seed}
+}
+
+// seededSource generates bytes with SplitMix64.
+type seededSource struct {
+	mu    sync.Mutex
+	state uint64
+	buf   [8]byte
+	n     int
+}
+
+func (s *seededSource) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range p {
+		if s.n == 0 {
+			s.state += 0x9e3779b97f4a7c15
+			z := s.state
+			z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+			z = (z ^ z>>27) * 0x94d049bb133111eb
+			binary.LittleEndian.PutUint64(s.buf[:], z^z>>31)
+			s.n = len(s.buf)
+		}
+		p[i] = s.buf[len(s.buf)-s.n]
+		s.n--
+	}
+	return len(p), nil
+}
+
+// mathRandSource reads from the global math/rand source. It is fast, but not cryptographically secure.
+type mathRandSource struct{}
+
+func (mathRandSource) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+var mathRandGenerator = NewGenerator(mathRandSource{})
@@ -0,0 +1,111 @@
+package goutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSeededSource(t *testing.T) {
+	whole := make([]byte, 100)
+	if _, err := io.ReadFull(NewSeededSource(42), whole); err != nil {
+		t.Fatal(err)
+	}
+
+	src := NewSeededSource(42)
+	var chunked []byte
+	for _, size := range []int{1, 3, 8, 13, 75} {
+		b := make([]byte, size)
+		if _, err := io.ReadFull(src, b); err != nil {
+			t.Fatal(err)
+		}
+		chunked = append(chunked, b...)
+	}
+	if !bytes.Equal(chunked, whole) {
+		t.Errorf("chunked reads = %x, want %x", chunked, whole)
+	}
+
+	other := make([]byte, 100)
+	io.ReadFull(NewSeededSource(43), other)
+	if bytes.Equal(other, whole) {
+		t.Error("different seeds produced the same bytes")
+	}
+}
+
+func TestRandOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  func(opts ...RandOption) any
+	}{
+		{"SecureRandom", func(opts ...RandOption) any { return SecureRandom(1_000_000, opts...) }},
+		{"GenerateToken", func(opts ...RandOption) any { return GenerateToken(16, opts...) }},
+		{"SecureIntRange", func(opts ...RandOption) any {
+			n, _ := SecureIntRange(-1000, 1000, opts...)
+			return n
+		}},
+		{"SecureChoice", func(opts ...RandOption) any {
+			s, _ := SecureChoice([]string{"a", "b", "c", "d", "e", "f", "g", "h"}, opts...)
+			return s
+		}},
+		{"Shuffle", func(opts ...RandOption) any {
+			s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+			Shuffle(&s, opts...)
+			return s
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first, second []any
+			for i := 0; i < 5; i++ {
+				src := NewSeededSource(uint64(i))
+				first = append(first, tt.gen(WithRandSource(src)))
+				ctx := ContextWithRandSource(context.Background(), NewSeededSource(uint64(i)))
+				second = append(second, tt.gen(WithRandContext(ctx)))
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("seeded results differ: %v and %v", first, second)
+			}
+			if reflect.DeepEqual(first[0], first[1]) && reflect.DeepEqual(first[1], first[2]) {
+				t.Errorf("different seeds produced the same results %v", first)
+			}
+		})
+	}
+}
+
+func TestWithRandContextWithoutSource(t *testing.T) {
+	g := applyRandOptions(mathRandGenerator, []RandOption{WithRandContext(context.Background())})
+	if _, ok := g.Source.(mathRandSource); !ok {
+		t.Errorf("source = %T, want the default source to be kept", g.Source)
+	}
+	if RandSourceFromContext(context.Background()) != nil {
+		t.Error("RandSourceFromContext() of an empty context is not nil")
+	}
+}
+
+func TestShufflePermutation(t *testing.T) {
+	s := make([]int, 50)
+	for i := range s {
+		s[i] = i
+	}
+	if err := Shuffle(&s, WithRandSource(NewSeededSource(7))); err != nil {
+		t.Fatal(err)
+	}
+	sorted := append([]int(nil), s...)
+	sort.Ints(sorted)
+	for i, v := range sorted {
+		if v != i {
+			t.Fatalf("Shuffle() lost or duplicated elements: %v", s)
+		}
+	}
+
+	if err := Shuffle(&s, WithRandSource(failingReader{})); err == nil {
+		t.Error("Shuffle() with a failing source returned no error")
+	}
+	var empty []int
+	if err := Shuffle(&empty, WithRandSource(failingReader{})); err != nil {
+		t.Errorf("Shuffle() of an empty slice error = %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package goutil
+
+import (
+	"crypto/subtle"
+	"fmt"
+)
+
+// redacted replaces secrets in formatted and marshalled output.
+const redacted = "[REDACTED]"
+
+// Secret holds sensitive bytes such as keys, tokens or passwords.
+// It compares in constant time and never reveals its content when formatted, logged or marshalled;
+// convert it to []byte to access the content.
+type Secret []byte
+
+// NewSecret returns a secret holding a copy of b.
+func NewSecret(b []byte) Secret {
+	return append(Secret(nil), b...)
+}
+
+// Equal reports whether the secret equals other. The time taken depends only on the lengths.
+func (s Secret) Equal(other []byte) bool {
+	return subtle.ConstantTimeCompare(s, other) == 1
+}
+
+// EqualString reports whether the secret equals other. The time taken depends only on the lengths.
+func (s Secret) EqualString(other string) bool {
+	return s.Equal([]byte(other))
+}
+
+// Zero overwrites the content of the secret with zeros, e.g. once a key is no longer needed.
+// Copies of the content made before are not affected.
+func (s Secret) Zero() {
+	for i := range s {
+		s[i] = 0
+	}
+}
+
+// String returns a placeholder instead of the content.
+func (s Secret) String() string {
+	return redacted
+}
+
+// GoString returns a placeholder instead of the content.
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// Format writes a placeholder instead of the content for every verb, including %x and %q.
+func (s Secret) Format(f fmt.State, verb rune) {
+	f.Write([]byte(redacted))
+}
+
+// MarshalJSON encodes the secret as a placeholder string.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// MarshalText encodes the secret as a placeholder.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// SecureEqual reports whether a and b are equal, in constant time.
+// The time taken depends only on the lengths, so it is safe for comparing tokens and codes.
+func SecureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// SecureEqualAny reports whether s equals any of the candidates, in constant time.
+// All candidates are compared, so the time taken does not reveal which one matched.
+func SecureEqualAny(s string, candidates ...string) bool {
+	match := 0
+	for _, c := range candidates {
+		match |= subtle.ConstantTimeCompare([]byte(s), []byte(c))
+	}
+	return match == 1
+}
@@ -0,0 +1,98 @@
+package goutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecretEqual(t *testing.T) {
+	s := Secret("token")
+	tests := []struct {
+		other string
+		want  bool
+	}{
+		{"token", true},
+		{"tokem", false},
+		{"toke", false},
+		{"token!", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.other, func(t *testing.T) {
+			if got := s.EqualString(tt.other); got != tt.want {
+				t.Errorf("EqualString(%q) = %v, want %v", tt.other, got, tt.want)
+			}
+			if got := s.Equal([]byte(tt.other)); got != tt.want {
+				t.Errorf("Equal(%q) = %v, want %v", tt.other, got, tt.want)
+			}
+			if got := SecureEqual("token", tt.other); got != tt.want {
+				t.Errorf("SecureEqual(%q) = %v, want %v", tt.other, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretRedaction(t *testing.T) {
+	s := NewSecret([]byte("hunter2"))
+	wrapped := struct {
+		Name     string
+		Password Secret
+	}{"alice", s}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%X", "%d"} {
+		if got := fmt.Sprintf(format, wrapped); strings.Contains(got, "hunter2") || strings.Contains(got, "68756e74657232") || strings.Contains(got, "104") {
+			t.Errorf("Sprintf(%q) = %s reveals the secret", format, got)
+		}
+	}
+	if s.String() != "[REDACTED]" {
+		t.Errorf("String() = %s", s)
+	}
+
+	data, err := json.Marshal(wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Name":"alice","Password":"[REDACTED]"}` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+	if string([]byte(s)) != "hunter2" {
+		t.Errorf("content = %q, want %q", []byte(s), "hunter2")
+	}
+}
+
+func TestSecretZero(t *testing.T) {
+	b := []byte("hunter2")
+	s := NewSecret(b)
+	s.Zero()
+	for i, v := range s {
+		if v != 0 {
+			t.Errorf("byte %d = %d after Zero()", i, v)
+		}
+	}
+	if string(b) != "hunter2" {
+		t.Errorf("Zero() changed the original bytes to %q", b)
+	}
+}
+
+func TestSecureEqualAny(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		candidates []string
+		want       bool
+	}{
+		{"first", "a", []string{"a", "b"}, true},
+		{"last", "b", []string{"a", "b"}, true},
+		{"none", "c", []string{"a", "b"}, false},
+		{"no candidates", "a", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SecureEqualAny(tt.s, tt.candidates...); got != tt.want {
+				t.Errorf("SecureEqualAny() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
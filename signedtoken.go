@@ -0,0 +1,147 @@
+package goutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// signedTokenVersion is the version of the token format produced by a TokenSigner.
+// The body of a token is laid out as version (1 byte), expiry in Unix seconds (8 bytes, big endian),
+// key id length (1 byte), key id and payload. A token is the body and its HMAC-SHA256 signature,
+// both unpadded URL-safe Base64 encoded and joined by a dot.
+const signedTokenVersion = 1
+
+// minSigningKeyLength is the minimum length of a token signing key in bytes.
+const minSigningKeyLength = 16
+
+// DefaultTokenSkew is the clock skew tolerated by VerifyToken.
+const DefaultTokenSkew = time.Minute
+
+var (
+	// ErrInvalidToken is returned when a signed token is malformed.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrInvalidSignature is returned when the signature of a token does not match.
+	ErrInvalidSignature = errors.New("invalid token signature")
+	// ErrTokenExpired is returned when a token has expired.
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// SignToken returns a URL-safe token carrying the payload, signed with the key and valid for ttl.
+// The key must have at least 16 bytes. The payload is signed, but not encrypted.
+func SignToken(payload, key []byte, ttl time.Duration) (string, error) {
+	return TokenSigner{Keys: map[string][]byte{"": key}}.Sign(payload, ttl)
+}
+
+// VerifyToken checks the signature and expiry of a token produced by SignToken and returns its payload.
+// Tokens are accepted up to DefaultTokenSkew after they expired.
+func VerifyToken(token string, key []byte) ([]byte, error) {
+	return TokenSigner{Keys: map[string][]byte{"": key}, Skew: DefaultTokenSkew}.Verify(token)
+}
+
+// TokenSigner signs and verifies expiring tokens with HMAC-SHA256.
+// Every token records the id of its key, so new keys can be introduced while tokens
+// signed with older keys stay valid.
+type TokenSigner struct {
+	// Keys maps key ids to keys of at least 16 bytes. Ids are at most 255 bytes long.
+	Keys map[string][]byte
+	// KeyID is the id of the key new tokens are signed with.
+	KeyID string
+	// Skew is the time a token is still accepted after it expired, to tolerate clock differences.
+	Skew time.Duration
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Sign returns a URL-safe token carrying the payload, valid for ttl.
+func (s TokenSigner) Sign(payload []byte, ttl time.Duration) (string, error) {
+	key, err := s.key(s.KeyID)
+	if err != nil {
+		return "", err
+	}
+	if len(s.KeyID) > 255 {
+		return "", fmt.Errorf("%w: key id is longer than 255 bytes", ErrInvalidKey)
+	}
+
+	body := make([]byte, 10, 10+len(s.KeyID)+len(payload))
+	body[0] = signedTokenVersion
+	binary.BigEndian.PutUint64(body[1:], uint64(TimeToUnix(s.now().Add(ttl))))
+	body[9] = byte(len(s.KeyID))
+	body = append(body, s.KeyID...)
+	body = append(body, payload...)
+	return base64.RawURLEncoding.EncodeToString(body) + "." + base64.RawURLEncoding.EncodeToString(tokenSignature(key, body)), nil
+}
+
+// Verify checks the signature and expiry of a token and returns its payload.
+func (s TokenSigner) Verify(token string) ([]byte, error) {
+	expires, payload, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if s.now().After(expires.Add(s.Skew)) {
+		return nil, fmt.Errorf("%w: at %v", ErrTokenExpired, expires)
+	}
+	return payload, nil
+}
+
+// parse verifies the signature of a token and returns its fields.
+func (s TokenSigner) parse(token string) (expires time.Time, payload []byte, err error) {
+	encodedBody, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return time.Time{}, nil, ErrInvalidToken
+	}
+	body, err := base64.RawURLEncoding.DecodeString(encodedBody)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if len(body) < 10 || len(body) < 10+int(body[9]) {
+		return time.Time{}, nil, ErrInvalidToken
+	}
+	if body[0] != signedTokenVersion {
+		return time.Time{}, nil, fmt.Errorf("%w: unknown version %d", ErrInvalidToken, body[0])
+	}
+
+	kid := string(body[10 : 10+int(body[9])])
+	key, err := s.key(kid)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if !Secret(signature).Equal(tokenSignature(key, body)) {
+		return time.Time{}, nil, ErrInvalidSignature
+	}
+	expires = UnixToTime(int64(binary.BigEndian.Uint64(body[1:])))
+	return expires, body[10+len(kid):], nil
+}
+
+func (s TokenSigner) key(kid string) ([]byte, error) {
+	key, ok := s.Keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if len(key) < minSigningKeyLength {
+		return nil, fmt.Errorf("%w: signing key must have at least %d bytes", ErrInvalidKey, minSigningKeyLength)
+	}
+	return key, nil
+}
+
+func (s TokenSigner) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+func tokenSignature(key, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
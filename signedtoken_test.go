@@ -0,0 +1,126 @@
+package goutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignToken(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	token, err := SignToken([]byte("reset:user@example.com"), key, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("SignToken() = %q is not URL-safe", token)
+	}
+
+	payload, err := VerifyToken(token, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != "reset:user@example.com" {
+		t.Errorf("VerifyToken() = %q, want %q", payload, "reset:user@example.com")
+	}
+
+	if _, err := VerifyToken(token, []byte("fedcba9876543210")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyToken() with other key = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := SignToken(nil, []byte("short"), time.Hour); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("SignToken() with short key = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestTokenSignerExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	signer := TokenSigner{
+		Keys:  map[string][]byte{"k1": []byte("0123456789abcdef")},
+		KeyID: "k1",
+		Skew:  30 * time.Second,
+		Now:   func() time.Time { return now },
+	}
+	token, err := signer.Sign([]byte("payload"), 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		wantErr error
+	}{
+		{"fresh", 0, nil},
+		{"before expiry", 10 * time.Minute, nil},
+		{"within skew", 10*time.Minute + 30*time.Second, nil},
+		{"expired", 10*time.Minute + 31*time.Second, ErrTokenExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := signer
+			verifier.Now = func() time.Time { return now.Add(tt.elapsed) }
+			_, err := verifier.Verify(token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenSignerRotation(t *testing.T) {
+	old := TokenSigner{Keys: map[string][]byte{"2023": []byte("old key of sixteen bytes")}, KeyID: "2023"}
+	token, err := old.Sign([]byte("payload"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := TokenSigner{
+		Keys: map[string][]byte{
+			"2023": []byte("old key of sixteen bytes"),
+			"2024": []byte("new key of sixteen bytes"),
+		},
+		KeyID: "2024",
+	}
+	if payload, err := current.Verify(token); err != nil || string(payload) != "payload" {
+		t.Errorf("Verify() of token signed with old key = %q, %v", payload, err)
+	}
+
+	newToken, err := current.Sign([]byte("payload"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Verify(newToken); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Verify() of token with unknown key id = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestVerifyTokenErrors(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	token, err := SignToken([]byte("payload"), key, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"empty", "", ErrInvalidToken},
+		{"no signature", body, ErrInvalidToken},
+		{"invalid base64", body + ".!!", ErrInvalidToken},
+		{"short body", "AQ." + signature, ErrInvalidToken},
+		{"unknown version", "Ag" + body[2:] + "." + signature, ErrInvalidToken},
+		{"changed body", body[:len(body)-1] + "x." + signature, ErrInvalidSignature},
+		{"changed signature", body + "." + signature[:len(signature)-2] + "AA", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyToken(tt.token, key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyToken(%q) = %v, want %v", tt.token, err, tt.wantErr)
+			}
+		})
+	}
+}
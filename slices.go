@@ -2,54 +2,155 @@ package goutil
 
 import (
 	"errors"
-	"math/rand"
 )
 
 // Take a slice of any orderable type and sort it in ascending order.
 // The type of the slice must be one of the types defined by the type Any, Number.
+// The sort runs in O(n log n) time using pattern-defeating quicksort and is not stable.
 func SortAsc[C Comparable](slice *[]C) error {
 	if slice == nil {
 		return errors.New("nil slice")
 	}
 
-	// Range through the slice and sort it.
-	for i := 0; i < len(*slice)-1; i++ {
-		for j := i + 1; j < len(*slice); j++ {
-			if (*slice)[i] > (*slice)[j] {
-				(*slice)[i], (*slice)[j] = (*slice)[j], (*slice)[i]
-			}
-		}
-	}
+	sortSlice(*slice, func(a, b C) bool { return a < b })
 	return nil
 }
 
 //Take a slice of any orderable type and sort it in descending order.
 //The type of the slice must be one of the types defined by the type Any, Number.
+//The sort runs in O(n log n) time using pattern-defeating quicksort and is not stable.
 func SortDesc[C Comparable](slice *[]C) error {
 	if slice == nil {
 		return errors.New("nil slice")
 	}
 
-	// Range through the slice and sort it.
-	for i := 0; i < len(*slice)-1; i++ {
-		for j := i + 1; j < len(*slice); j++ {
-			if (*slice)[i] < (*slice)[j] {
-				(*slice)[i], (*slice)[j] = (*slice)[j], (*slice)[i]
-			}
-		}
+	sortSlice(*slice, func(a, b C) bool { return a > b })
+	return nil
+}
+
+// Take a slice of any type and sort it by less, which reports whether a must sort before b.
+// Use Comparator.Less to sort by several keys. The sort is not stable.
+func SortFunc[A any](slice *[]A, less func(a, b A) bool) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	sortSlice(*slice, less)
+	return nil
+}
+
+// Take a slice of any type and sort it in ascending order of the keys returned by key.
+// Each key is computed only once, so key may be expensive. The sort is not stable.
+func SortBy[A any, K Comparable](slice *[]A, key func(A) K) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	sortByKey(*slice, key, sortSlice[keyedElement[A, K]])
+	return nil
+}
+
+// Take a slice of any orderable type and sort it in ascending order.
+// Equal elements keep their original order. The sort is a merge sort running in O(n log n) time.
+func StableSortAsc[C Comparable](slice *[]C) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	stableSortSlice(*slice, func(a, b C) bool { return a < b })
+	return nil
+}
+
+// Take a slice of any orderable type and sort it in descending order.
+// Equal elements keep their original order. The sort is a merge sort running in O(n log n) time.
+func StableSortDesc[C Comparable](slice *[]C) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	stableSortSlice(*slice, func(a, b C) bool { return a > b })
+	return nil
+}
+
+// Take a slice of any type and sort it by less, keeping equal elements in their original order.
+// Sorting by a secondary key and then by a primary key orders by both.
+func StableSortFunc[A any](slice *[]A, less func(a, b A) bool) error {
+	if slice == nil {
+		return errors.New("nil slice")
 	}
+
+	stableSortSlice(*slice, less)
 	return nil
 }
 
-// Take a slice of any type and shuffle it.
-func Shuffle[A any](slice *[]A) error {
+// Take a slice of any type and sort it in ascending order of the keys returned by key,
+// keeping elements with equal keys in their original order. Each key is computed only once.
+func StableSortBy[A any, K Comparable](slice *[]A, key func(A) K) error {
 	if slice == nil {
 		return errors.New("nil slice")
 	}
 
-	// Range through the slice and shuffle it.
-	for i := 0; i < len(*slice); i++ {
-		j := rand.Intn(len(*slice))
+	sortByKey(*slice, key, stableSortSlice[keyedElement[A, K]])
+	return nil
+}
+
+// IsSorted reports whether the slice is sorted in ascending order.
+func IsSorted[C Comparable](slice []C) bool {
+	return IsSortedUntil(slice) == len(slice)
+}
+
+// IsSortedFunc reports whether the slice is sorted by less.
+func IsSortedFunc[A any](slice []A, less func(a, b A) bool) bool {
+	return IsSortedUntilFunc(slice, less) == len(slice)
+}
+
+// IsSortedBy reports whether the slice is sorted in ascending order of the keys returned by key.
+func IsSortedBy[A any, K Comparable](slice []A, key func(A) K) bool {
+	return IsSortedUntilFunc(slice, func(a, b A) bool { return key(a) < key(b) }) == len(slice)
+}
+
+// IsSortedUntil returns the index of the first element that is less than its predecessor,
+// or len(slice) if the slice is sorted in ascending order.
+func IsSortedUntil[C Comparable](slice []C) int {
+	return sortedUntil(slice, func(a, b C) bool { return a < b })
+}
+
+// IsSortedUntilFunc is like IsSortedUntil, but compares the elements with less.
+func IsSortedUntilFunc[A any](slice []A, less func(a, b A) bool) int {
+	return sortedUntil(slice, less)
+}
+
+// sortByKey sorts the elements together with their keys using sort and writes them back.
+func sortByKey[A any, K Comparable](slice []A, key func(A) K, sort func([]keyedElement[A, K], func(a, b keyedElement[A, K]) bool)) {
+	keyed := make([]keyedElement[A, K], len(slice))
+	for i, v := range slice {
+		keyed[i] = keyedElement[A, K]{key: key(v), value: v}
+	}
+	sort(keyed, func(a, b keyedElement[A, K]) bool { return a.key < b.key })
+	for i, e := range keyed {
+		slice[i] = e.value
+	}
+}
+
+type keyedElement[A any, K Comparable] struct {
+	key   K
+	value A
+}
+
+// Take a slice of any type and shuffle it with the Fisher-Yates algorithm.
+// By default the global math/rand source is used; options select another RandSource,
+// e.g. crypto/rand with WithRandSource(nil) or a seeded one in tests.
+func Shuffle[A any](slice *[]A, opts ...RandOption) error {
+	if slice == nil {
+		return errors.New("nil slice")
+	}
+
+	g := applyRandOptions(mathRandGenerator, opts)
+	for i := len(*slice) - 1; i > 0; i-- {
+		j, err := g.Uint64n(uint64(i + 1))
+		if err != nil {
+			return err
+		}
 		(*slice)[i], (*slice)[j] = (*slice)[j], (*slice)[i]
 	}
 	return nil
@@ -3,6 +3,7 @@ package goutil
 import (
 	"math/rand"
 	"reflect"
+	"sort"
 	"strconv"
 	"testing"
 )
@@ -197,3 +198,148 @@ func TestCopy(t *testing.T) {
 
 	})
 }
+
+func TestSortFunc(t *testing.T) {
+	got := []string{"banana", "kiwi", "apple", "fig", "cherry"}
+	want := []string{"fig", "kiwi", "apple", "banana", "cherry"}
+	err := SortFunc(&got, func(a, b string) bool {
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := SortFunc[int](nil, func(a, b int) bool { return a < b }); err == nil {
+		t.Error("SortFunc(nil) returned no error")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	got := []string{"b", "ccc", "dddd", "aa", ""}
+	want := []string{"dddd", "ccc", "aa", "b", ""}
+	calls := 0
+	err := SortBy(&got, func(s string) int {
+		calls++
+		return -len(s)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if calls != len(got) {
+		t.Errorf("key called %d times, want %d", calls, len(got))
+	}
+
+	if err := SortBy[int, int](nil, func(v int) int { return v }); err == nil {
+		t.Error("SortBy(nil) returned no error")
+	}
+}
+
+func TestStableSort(t *testing.T) {
+	type pair struct{ key, index int }
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 19, 20, 21, 100, 1000, 12345} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			got := make([]pair, n)
+			for i := range got {
+				got[i] = pair{r.Intn(n/4 + 1), i}
+			}
+			want := make([]pair, n)
+			copy(want, got)
+			sort.SliceStable(want, func(i, j int) bool { return want[i].key < want[j].key })
+
+			byKey := make([]pair, n)
+			copy(byKey, got)
+			if err := StableSortFunc(&got, func(a, b pair) bool { return a.key < b.key }); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("StableSortFunc() is not stable")
+			}
+			if err := StableSortBy(&byKey, func(p pair) int { return p.key }); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(byKey, want) {
+				t.Errorf("StableSortBy() is not stable")
+			}
+		})
+	}
+}
+
+func TestStableSortSecondaryKeys(t *testing.T) {
+	type row struct {
+		name string
+		team string
+	}
+	rows := []row{{"dave", "red"}, {"alice", "blue"}, {"carol", "red"}, {"bob", "blue"}, {"erin", "green"}}
+	want := []row{{"alice", "blue"}, {"bob", "blue"}, {"erin", "green"}, {"carol", "red"}, {"dave", "red"}}
+
+	StableSortBy(&rows, func(r row) string { return r.name })
+	StableSortBy(&rows, func(r row) string { return r.team })
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %v, want %v", rows, want)
+	}
+}
+
+func TestStableSortAscDesc(t *testing.T) {
+	asc := []float64{3.5, -1, 2, 2, 0, 10}
+	if err := StableSortAsc(&asc); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{-1, 0, 2, 2, 3.5, 10}; !reflect.DeepEqual(asc, want) {
+		t.Errorf("StableSortAsc() = %v, want %v", asc, want)
+	}
+	desc := []string{"b", "c", "a", "c"}
+	if err := StableSortDesc(&desc); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"c", "c", "b", "a"}; !reflect.DeepEqual(desc, want) {
+		t.Errorf("StableSortDesc() = %v, want %v", desc, want)
+	}
+	if err := StableSortAsc[int](nil); err == nil {
+		t.Error("StableSortAsc(nil) returned no error")
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice []int
+		until int
+	}{
+		{"nil", nil, 0},
+		{"single", []int{1}, 1},
+		{"sorted", []int{1, 2, 2, 3}, 4},
+		{"unsorted at start", []int{2, 1, 3}, 1},
+		{"unsorted at end", []int{1, 2, 4, 3}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSortedUntil(tt.slice); got != tt.until {
+				t.Errorf("IsSortedUntil() = %d, want %d", got, tt.until)
+			}
+			sorted := tt.until == len(tt.slice)
+			if got := IsSorted(tt.slice); got != sorted {
+				t.Errorf("IsSorted() = %v, want %v", got, sorted)
+			}
+			less := func(a, b int) bool { return a < b }
+			if got := IsSortedFunc(tt.slice, less); got != sorted {
+				t.Errorf("IsSortedFunc() = %v, want %v", got, sorted)
+			}
+			if got := IsSortedUntilFunc(tt.slice, less); got != tt.until {
+				t.Errorf("IsSortedUntilFunc() = %d, want %d", got, tt.until)
+			}
+			if got := IsSortedBy(tt.slice, func(v int) int { return v }); got != sorted {
+				t.Errorf("IsSortedBy() = %v, want %v", got, sorted)
+			}
+		})
+	}
+}
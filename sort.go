@@ -0,0 +1,357 @@
+package goutil
+
+import "math/bits"
+
+// Pattern-defeating quicksort (pdqsort) by Orson Peters, as used by the Go standard library since 1.19.
+// It runs in O(n log n) worst case time, falling back to heapsort when partitioning goes badly,
+// and in linear time on sorted, reversed and many other patterned inputs.
+
+// maxInsertion is the length up to which ranges are sorted with insertion sort.
+const maxInsertion = 12
+
+type sortedHint int
+
+const (
+	unknownHint sortedHint = iota
+	increasingHint
+	decreasingHint
+)
+
+// pdqSorter sorts data by less, which must be a strict weak ordering. The sort is not stable.
+type pdqSorter[E any] struct {
+	data []E
+	less func(a, b E) bool
+}
+
+// sortSlice sorts data in place by less.
+func sortSlice[E any](data []E, less func(a, b E) bool) {
+	s := pdqSorter[E]{data: data, less: less}
+	s.pdqsort(0, len(data), bits.Len(uint(len(data))))
+}
+
+func (s pdqSorter[E]) swap(i, j int) {
+	s.data[i], s.data[j] = s.data[j], s.data[i]
+}
+
+func (s pdqSorter[E]) insertionSort(a, b int) {
+	for i := a + 1; i < b; i++ {
+		for j := i; j > a && s.less(s.data[j], s.data[j-1]); j-- {
+			s.swap(j, j-1)
+		}
+	}
+}
+
+// siftDown restores the heap property of data[first+lo:first+hi] below root.
+func (s pdqSorter[E]) siftDown(lo, hi, first int) {
+	root := lo
+	for {
+		child := 2*root + 1
+		if child >= hi {
+			return
+		}
+		if child+1 < hi && s.less(s.data[first+child], s.data[first+child+1]) {
+			child++
+		}
+		if !s.less(s.data[first+root], s.data[first+child]) {
+			return
+		}
+		s.swap(first+root, first+child)
+		root = child
+	}
+}
+
+func (s pdqSorter[E]) heapSort(a, b int) {
+	first, hi := a, b-a
+	for i := (hi - 1) / 2; i >= 0; i-- {
+		s.siftDown(i, hi, first)
+	}
+	for i := hi - 1; i >= 0; i-- {
+		s.swap(first, first+i)
+		s.siftDown(0, i, first)
+	}
+}
+
+// pdqsort sorts data[a:b]. limit is the number of imbalanced partitions allowed before falling back to heapsort.
+func (s pdqSorter[E]) pdqsort(a, b, limit int) {
+	wasBalanced, wasPartitioned := true, true
+	for {
+		length := b - a
+		if length <= maxInsertion {
+			s.insertionSort(a, b)
+			return
+		}
+		if limit == 0 {
+			s.heapSort(a, b)
+			return
+		}
+		if !wasBalanced {
+			s.breakPatterns(a, b)
+			limit--
+		}
+
+		pivot, hint := s.choosePivot(a, b)
+		if hint == decreasingHint {
+			s.reverseRange(a, b)
+			pivot = (b - 1) - (pivot - a)
+			hint = increasingHint
+		}
+		// The range is probably sorted already.
+		if wasBalanced && wasPartitioned && hint == increasingHint && s.partialInsertionSort(a, b) {
+			return
+		}
+		// The pivot equals the pivot of the enclosing partition, which is not greater than any
+		// element of the range, so put all elements equal to it first and skip them.
+		if a > 0 && !s.less(s.data[a-1], s.data[pivot]) {
+			a = s.partitionEqual(a, b, pivot)
+			continue
+		}
+
+		mid, alreadyPartitioned := s.partition(a, b, pivot)
+		wasPartitioned = alreadyPartitioned
+		left, right := mid-a, b-mid
+		// Recurse into the smaller side to bound the stack depth.
+		if left < right {
+			wasBalanced = left >= length/8
+			s.pdqsort(a, mid, limit)
+			a = mid + 1
+		} else {
+			wasBalanced = right >= length/8
+			s.pdqsort(mid+1, b, limit)
+			b = mid
+		}
+	}
+}
+
+// partition moves the elements less than the pivot before it and returns its new position,
+// and whether no elements had to be moved.
+func (s pdqSorter[E]) partition(a, b, pivot int) (int, bool) {
+	s.swap(a, pivot)
+	i, j := a+1, b-1
+	for i <= j && s.less(s.data[i], s.data[a]) {
+		i++
+	}
+	for i <= j && !s.less(s.data[j], s.data[a]) {
+		j--
+	}
+	if i > j {
+		s.swap(j, a)
+		return j, true
+	}
+	s.swap(i, j)
+	i++
+	j--
+
+	for {
+		for i <= j && s.less(s.data[i], s.data[a]) {
+			i++
+		}
+		for i <= j && !s.less(s.data[j], s.data[a]) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		s.swap(i, j)
+		i++
+		j--
+	}
+	s.swap(j, a)
+	return j, false
+}
+
+// partitionEqual moves the elements equal to the pivot first and returns the index of the first greater one.
+func (s pdqSorter[E]) partitionEqual(a, b, pivot int) int {
+	s.swap(a, pivot)
+	i, j := a+1, b-1
+	for {
+		for i <= j && !s.less(s.data[a], s.data[i]) {
+			i++
+		}
+		for i <= j && s.less(s.data[a], s.data[j]) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		s.swap(i, j)
+		i++
+		j--
+	}
+	return i
+}
+
+// partialInsertionSort sorts a nearly sorted range by moving a few misplaced elements
+// and reports whether it succeeded.
+func (s pdqSorter[E]) partialInsertionSort(a, b int) bool {
+	const (
+		maxSteps         = 5
+		shortestShifting = 50
+	)
+	i := a + 1
+	for step := 0; step < maxSteps; step++ {
+		for i < b && !s.less(s.data[i], s.data[i-1]) {
+			i++
+		}
+		if i == b {
+			return true
+		}
+		if b-a < shortestShifting {
+			return false
+		}
+		s.swap(i, i-1)
+
+		// Shift the smaller element left and the greater one right.
+		for j := i - 1; j >= 1 && s.less(s.data[j], s.data[j-1]); j-- {
+			s.swap(j, j-1)
+		}
+		for j := i + 1; j < b && s.less(s.data[j], s.data[j-1]); j++ {
+			s.swap(j, j-1)
+		}
+	}
+	return false
+}
+
+// breakPatterns swaps a few elements around the middle pseudo-randomly to defeat adversarial inputs.
+func (s pdqSorter[E]) breakPatterns(a, b int) {
+	length := b - a
+	if length < 8 {
+		return
+	}
+	random := uint64(length)
+	modulus := uint(1) << bits.Len(uint(length))
+	idx := a + (length/4)*2 - 1
+	for i := 0; i < 3; i++ {
+		random ^= random << 13
+		random ^= random >> 7
+		random ^= random << 17
+		other := int(uint(random) & (modulus - 1))
+		if other >= length {
+			other -= length
+		}
+		s.swap(idx-1+i, a+other)
+	}
+}
+
+// choosePivot returns the median of three or, for long ranges, the median of three medians (Tukey's ninther).
+// The hint tells whether the sampled elements were in increasing or decreasing order.
+func (s pdqSorter[E]) choosePivot(a, b int) (int, sortedHint) {
+	const (
+		shortestNinther = 50
+		maxSwaps        = 4 * 3
+	)
+	length := b - a
+	var swaps int
+	i, j, k := a+length/4, a+length/4*2, a+length/4*3
+	if length >= 8 {
+		if length >= shortestNinther {
+			i = s.medianAdjacent(i, &swaps)
+			j = s.medianAdjacent(j, &swaps)
+			k = s.medianAdjacent(k, &swaps)
+		}
+		j = s.median(i, j, k, &swaps)
+	}
+	switch swaps {
+	case 0:
+		return j, increasingHint
+	case maxSwaps:
+		return j, decreasingHint
+	default:
+		return j, unknownHint
+	}
+}
+
+func (s pdqSorter[E]) order2(a, b int, swaps *int) (int, int) {
+	if s.less(s.data[b], s.data[a]) {
+		*swaps++
+		return b, a
+	}
+	return a, b
+}
+
+func (s pdqSorter[E]) median(a, b, c int, swaps *int) int {
+	a, b = s.order2(a, b, swaps)
+	b, c = s.order2(b, c, swaps)
+	_, b = s.order2(a, b, swaps)
+	return b
+}
+
+func (s pdqSorter[E]) medianAdjacent(a int, swaps *int) int {
+	return s.median(a-1, a, a+1, swaps)
+}
+
+func (s pdqSorter[E]) reverseRange(a, b int) {
+	for i, j := a, b-1; i < j; i, j = i+1, j-1 {
+		s.swap(i, j)
+	}
+}
+
+// stableBlockSize is the length of the runs sorted with insertion sort before merging.
+const stableBlockSize = 20
+
+// stableSortSlice sorts data in place by less, keeping equal elements in their original order.
+// It is a bottom-up merge sort taking O(n log n) time and O(n) extra space.
+func stableSortSlice[E any](data []E, less func(a, b E) bool) {
+	n := len(data)
+	s := pdqSorter[E]{data: data, less: less}
+	for a := 0; a < n; a += stableBlockSize {
+		b := a + stableBlockSize
+		if b > n {
+			b = n
+		}
+		s.insertionSort(a, b)
+	}
+	if n <= stableBlockSize {
+		return
+	}
+
+	// Merge runs of doubling width, alternating between data and the buffer.
+	src, dst := data, make([]E, n)
+	for width := stableBlockSize; width < n; width *= 2 {
+		for lo := 0; lo < n; lo += 2 * width {
+			mid, hi := lo+width, lo+2*width
+			if mid > n {
+				mid = n
+			}
+			if hi > n {
+				hi = n
+			}
+			merge(dst[lo:hi], src[lo:mid], src[mid:hi], less)
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
+
+// merge merges the sorted slices left and right into dst, taking equal elements from left first.
+func merge[E any](dst, left, right []E, less func(a, b E) bool) {
+	// The runs are already in order, which is common for partially sorted input.
+	if len(left) == 0 || len(right) == 0 || !less(right[0], left[len(left)-1]) {
+		copy(dst[copy(dst, left):], right)
+		return
+	}
+	i, j, k := 0, 0, 0
+	for i < len(left) && j < len(right) {
+		if less(right[j], left[i]) {
+			dst[k] = right[j]
+			j++
+		} else {
+			dst[k] = left[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], left[i:])
+	copy(dst[k:], right[j:])
+}
+
+// sortedUntil returns the index of the first element of data less than its predecessor, or len(data).
+func sortedUntil[E any](data []E, less func(a, b E) bool) int {
+	for i := 1; i < len(data); i++ {
+		if less(data[i], data[i-1]) {
+			return i
+		}
+	}
+	return len(data)
+}
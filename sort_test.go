@@ -0,0 +1,122 @@
+package goutil
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// sortInputs returns inputs of length n with patterns that are known to be hard for quicksorts.
+func sortInputs(n int) map[string][]int {
+	r := rand.New(rand.NewSource(int64(n)))
+	inputs := map[string][]int{
+		"random":      make([]int, n),
+		"sorted":      make([]int, n),
+		"reversed":    make([]int, n),
+		"duplicates":  make([]int, n),
+		"nearly":      make([]int, n),
+		"sawtooth":    make([]int, n),
+		"organ pipe":  make([]int, n),
+		"all equal":   make([]int, n),
+		"sorted tail": make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		inputs["random"][i] = r.Int()
+		inputs["sorted"][i] = i
+		inputs["reversed"][i] = n - i
+		inputs["duplicates"][i] = r.Intn(8)
+		inputs["nearly"][i] = i
+		inputs["sawtooth"][i] = i % 17
+		inputs["organ pipe"][i] = i
+		if i >= n/2 {
+			inputs["organ pipe"][i] = n - i
+		}
+		inputs["sorted tail"][i] = i
+		if i < n/10 {
+			inputs["sorted tail"][i] = r.Intn(n + 1)
+		}
+	}
+	for i := 0; i < n/20; i++ {
+		a, b := r.Intn(n), r.Intn(n)
+		inputs["nearly"][a], inputs["nearly"][b] = inputs["nearly"][b], inputs["nearly"][a]
+	}
+	return inputs
+}
+
+func TestSortSlice(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 12, 13, 50, 51, 1000, 100000} {
+		for name, input := range sortInputs(n) {
+			t.Run(fmt.Sprintf("%s %d", name, n), func(t *testing.T) {
+				want := append([]int(nil), input...)
+				sort.Ints(want)
+
+				got := append([]int(nil), input...)
+				sortSlice(got, func(a, b int) bool { return a < b })
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("element %d = %d, want %d", i, got[i], want[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSortSliceComparisons(t *testing.T) {
+	for name, input := range sortInputs(10000) {
+		t.Run(name, func(t *testing.T) {
+			comparisons := 0
+			sortSlice(input, func(a, b int) bool {
+				comparisons++
+				return a < b
+			})
+			// n log n with a generous constant; the former exchange sort needed n²/2.
+			if limit := 3 * 10000 * 14; comparisons > limit {
+				t.Errorf("%d comparisons, want at most %d", comparisons, limit)
+			}
+		})
+	}
+}
+
+func TestHeapSortFallback(t *testing.T) {
+	data := sortInputs(1000)["random"]
+	s := pdqSorter[int]{data: data, less: func(a, b int) bool { return a < b }}
+	s.pdqsort(0, len(data), 0)
+	if !sort.IntsAreSorted(data) {
+		t.Error("slice is not sorted")
+	}
+}
+
+func TestSortDescLarge(t *testing.T) {
+	got := sortInputs(5000)["random"]
+	if err := SortDesc(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !sort.IsSorted(sort.Reverse(sort.IntSlice(got))) {
+		t.Error("slice is not sorted in descending order")
+	}
+}
+
+func BenchmarkSortAsc(b *testing.B) {
+	for _, n := range []int{1e3, 1e4, 1e5, 1e6, 1e7} {
+		r := rand.New(rand.NewSource(int64(n)))
+		input := make([]int, n)
+		for i := range input {
+			input[i] = r.Int()
+		}
+		data := make([]int, n)
+		b.Run(fmt.Sprintf("SortAsc %d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(data, input)
+				SortAsc(&data)
+			}
+		})
+		b.Run(fmt.Sprintf("sort.Slice %d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(data, input)
+				sort.Slice(data, func(i, j int) bool { return data[i] < data[j] })
+			}
+		})
+	}
+}
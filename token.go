@@ -0,0 +1,159 @@
+package goutil
+
+import (
+	"errors"
+	"hash/crc32"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Character sets for tokens.
+const (
+	CharsetHex       = "0123456789abcdef"
+	CharsetCrockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ" // Crockford's base32
+	CharsetBase58    = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	CharsetBase62    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	CharsetBase64URL = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_" // unpadded base64url
+)
+
+// ErrInvalidCharset is returned when a character set has less than two characters or contains duplicates.
+var ErrInvalidCharset = errors.New("charset must contain at least two unique characters")
+
+// defaultTokenEntropy is the entropy of a token if neither Length nor EntropyBits is set.
+const defaultTokenEntropy = 128
+
+// TokenGenerator generates random tokens with a configurable format.
+// The zero value generates base62 tokens with 128 bits of entropy.
+type TokenGenerator struct {
+	// Charset contains the characters a token is made of. Defaults to CharsetBase62.
+	Charset string
+	// Length is the number of random characters of a token.
+	Length int
+	// EntropyBits is the minimum entropy of a token. If set, it takes precedence over Length.
+	EntropyBits int
+	// Prefix is prepended to every token, e.g. "sk_live_".
+	Prefix string
+	// Checksum appends a CRC32 checksum, encoded in the charset, to every token.
+	// It allows scanners to detect leaked tokens with a low false-positive rate.
+	Checksum bool
+	// Generator is the source of randomness. Defaults to crypto/rand.
+	Generator *Generator
+}
+
+// Generate returns a new random token.
+func (tg TokenGenerator) Generate() (string, error) {
+	charset, err := tg.charset()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tg.Prefix)
+	for i := 0; i < tg.length(len(charset)); i++ {
+		n, err := tg.Generator.Uint64n(uint64(len(charset)))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteRune(charset[n])
+	}
+	if tg.Checksum {
+		sb.WriteString(tokenChecksum(sb.String(), charset))
+	}
+	return sb.String(), nil
+}
+
+// Valid reports whether the token could have been generated by tg.
+// It checks the prefix, the length, the characters and, if enabled, the checksum.
+func (tg TokenGenerator) Valid(token string) bool {
+	charset, err := tg.charset()
+	if err != nil || !strings.HasPrefix(token, tg.Prefix) {
+		return false
+	}
+
+	body := []rune(strings.TrimPrefix(token, tg.Prefix))
+	length := tg.length(len(charset))
+	checksumLength := 0
+	if tg.Checksum {
+		checksumLength = checksumWidth(len(charset))
+	}
+	if len(body) != length+checksumLength {
+		return false
+	}
+	for _, r := range body {
+		if !containsRune(charset, r) {
+			return false
+		}
+	}
+	if !tg.Checksum {
+		return true
+	}
+	payload := tg.Prefix + string(body[:length])
+	return SecureEqual(tokenChecksum(payload, charset), string(body[length:]))
+}
+
+// Entropy returns the number of random bits in a token.
+func (tg TokenGenerator) Entropy() float64 {
+	charset, err := tg.charset()
+	if err != nil {
+		return 0
+	}
+	return float64(tg.length(len(charset))) * math.Log2(float64(len(charset)))
+}
+
+func (tg TokenGenerator) charset() ([]rune, error) {
+	if tg.Charset == "" {
+		return []rune(CharsetBase62), nil
+	}
+	if !utf8.ValidString(tg.Charset) {
+		return nil, ErrInvalidCharset
+	}
+	charset := []rune(tg.Charset)
+	seen := make(map[rune]bool, len(charset))
+	for _, r := range charset {
+		if seen[r] {
+			return nil, ErrInvalidCharset
+		}
+		seen[r] = true
+	}
+	if len(charset) < 2 {
+		return nil, ErrInvalidCharset
+	}
+	return charset, nil
+}
+
+// length returns the number of random characters for a charset of the given size.
+func (tg TokenGenerator) length(size int) int {
+	bits := tg.EntropyBits
+	if bits <= 0 {
+		if tg.Length > 0 {
+			return tg.Length
+		}
+		bits = defaultTokenEntropy
+	}
+	return int(math.Ceil(float64(bits) / math.Log2(float64(size))))
+}
+
+// checksumWidth returns the number of characters needed to encode a CRC32 checksum.
+func checksumWidth(size int) int {
+	return int(math.Ceil(32 / math.Log2(float64(size))))
+}
+
+func tokenChecksum(payload string, charset []rune) string {
+	sum := uint64(crc32.ChecksumIEEE([]byte(payload)))
+	out := make([]rune, checksumWidth(len(charset)))
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = charset[sum%uint64(len(charset))]
+		sum /= uint64(len(charset))
+	}
+	return string(out)
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, v := range runes {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
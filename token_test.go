@@ -0,0 +1,119 @@
+package goutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenGenerator(t *testing.T) {
+	tests := []struct {
+		name       string
+		generator  TokenGenerator
+		wantLength int
+	}{
+		{
+			name:       "Default",
+			generator:  TokenGenerator{},
+			wantLength: 22,
+		},
+		{
+			name:       "Hex entropy",
+			generator:  TokenGenerator{Charset: CharsetHex, EntropyBits: 128},
+			wantLength: 32,
+		},
+		{
+			name:       "Crockford length",
+			generator:  TokenGenerator{Charset: CharsetCrockford, Length: 26},
+			wantLength: 26,
+		},
+		{
+			name:       "Base58 with prefix",
+			generator:  TokenGenerator{Charset: CharsetBase58, Length: 20, Prefix: "sk_live_"},
+			wantLength: 28,
+		},
+		{
+			name:       "Base64URL with checksum",
+			generator:  TokenGenerator{Charset: CharsetBase64URL, EntropyBits: 96, Checksum: true},
+			wantLength: 22,
+		},
+		{
+			name:       "Custom alphabet",
+			generator:  TokenGenerator{Charset: "äöü", Length: 10, Prefix: "tok_", Checksum: true},
+			wantLength: 35,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.generator.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if length := len([]rune(got)); length != test.wantLength {
+				t.Errorf("Generate() = %s with length %v, want length %v", got, length, test.wantLength)
+			}
+			if !strings.HasPrefix(got, test.generator.Prefix) {
+				t.Errorf("Generate() = %s, want prefix %s", got, test.generator.Prefix)
+			}
+			if !test.generator.Valid(got) {
+				t.Errorf("Valid(%s) = false, want true", got)
+			}
+		})
+	}
+}
+
+func TestTokenGeneratorChecksum(t *testing.T) {
+	tg := TokenGenerator{Prefix: "sk_live_", Length: 30, Checksum: true}
+	token, err := tg.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []rune(token)
+	i := len(tg.Prefix)
+	if body[i] == 'a' {
+		body[i] = 'b'
+	} else {
+		body[i] = 'a'
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"Generated", token, true},
+		{"Tampered", string(body), false},
+		{"Wrong prefix", "sk_test_" + strings.TrimPrefix(token, tg.Prefix), false},
+		{"Truncated", token[:len(token)-1], false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := tg.Valid(test.token); got != test.want {
+				t.Errorf("Valid(%s) = %v, want %v", test.token, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenGeneratorEntropy(t *testing.T) {
+	tg := TokenGenerator{Charset: CharsetHex, Length: 16}
+	if got := tg.Entropy(); got != 64 {
+		t.Errorf("Entropy() = %v, want 64", got)
+	}
+
+	tg = TokenGenerator{Charset: CharsetBase62, EntropyBits: 128}
+	if got := tg.Entropy(); got < 128 {
+		t.Errorf("Entropy() = %v, want at least 128", got)
+	}
+}
+
+func TestTokenGeneratorInvalidCharset(t *testing.T) {
+	for _, charset := range []string{"a", "abca", "\xff\xfe"} {
+		tg := TokenGenerator{Charset: charset}
+		if _, err := tg.Generate(); err != ErrInvalidCharset {
+			t.Errorf("Generate() with charset %q error = %v, want %v", charset, err, ErrInvalidCharset)
+		}
+	}
+}
@@ -14,3 +14,8 @@ type Number interface {
 type Comparable interface {
 	Number | string
 }
+
+type Integer interface {
+	int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64
+}
@@ -0,0 +1,169 @@
+package goutil
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrULIDOverflow is returned when a monotonic ULID generator has exhausted
+// the random part of a millisecond.
+var ErrULIDOverflow = errors.New("ulid random part overflow")
+
+// maxULIDTime is the largest timestamp representable in a ULID.
+const maxULIDTime = 1<<48 - 1
+
+// ULID is a universally unique lexicographically sortable identifier: a 48 bit Unix time
+// in milliseconds followed by 80 random bits, encoded in Crockford's base32.
+// It is marshalled as text and JSON in its 26 characters string form.
+type ULID [16]byte
+
+// NewULID returns a ULID for the current time.
+// Options select another RandSource, e.g. a seeded one in tests.
+func NewULID(opts ...RandOption) (ULID, error) {
+	return (&ULIDGenerator{Generator: applyRandOptions(defaultGenerator, opts)}).New()
+}
+
+// ParseULID parses the 26 characters string form of a ULID, ignoring case.
+func ParseULID(s string) (ULID, error) {
+	if len(s) != 26 {
+		return ULID{}, fmt.Errorf("%w: %q is not a ULID", ErrInvalidID, s)
+	}
+
+	// The 26 characters hold 130 bits, so the first one must not exceed 7.
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := crockfordValue(s[i])
+		if v < 0 || (i == 0 && v > 7) {
+			return ULID{}, fmt.Errorf("%w: %q is not a ULID", ErrInvalidID, s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	var u ULID
+	for i := 0; i < 8; i++ {
+		u[i] = byte(hi >> (56 - 8*i))
+		u[8+i] = byte(lo >> (56 - 8*i))
+	}
+	return u, nil
+}
+
+// String returns the ULID in its 26 characters upper case form.
+func (u ULID) String() string {
+	var hi, lo uint64
+	for i := 0; i < 8; i++ {
+		hi = hi<<8 | uint64(u[i])
+		lo = lo<<8 | uint64(u[8+i])
+	}
+
+	buf := make([]byte, 26)
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = CharsetCrockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf)
+}
+
+// Time returns the creation time of the ULID.
+func (u ULID) Time() time.Time {
+	return MillisToTime(int64(uint48(u[:6])))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ULIDGenerator generates ULIDs. It is safe for concurrent use.
+type ULIDGenerator struct {
+	// Monotonic makes ULIDs strictly increasing: within the same millisecond, the random part of
+	// the previous ULID is incremented instead of drawing new random bits.
+	Monotonic bool
+	// Generator is the source of randomness. Defaults to crypto/rand.
+	Generator *Generator
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu   sync.Mutex
+	last ULID
+}
+
+// New returns a ULID for the current time.
+func (g *ULIDGenerator) New() (ULID, error) {
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+	ms := TimeToMillis(now)
+	if ms < 0 || ms > maxULIDTime {
+		return ULID{}, fmt.Errorf("%w: time %v out of range", ErrInvalidID, now)
+	}
+
+	if !g.Monotonic {
+		return g.random(uint64(ms))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// A clock going backwards is treated like the same millisecond, to keep the order.
+	if last := uint48(g.last[:6]); uint64(ms) > last || g.last == (ULID{}) {
+		u, err := g.random(uint64(ms))
+		if err != nil {
+			return ULID{}, err
+		}
+		g.last = u
+		return u, nil
+	}
+
+	u := g.last
+	for i := len(u) - 1; i >= 6; i-- {
+		u[i]++
+		if u[i] != 0 {
+			g.last = u
+			return u, nil
+		}
+	}
+	return ULID{}, ErrULIDOverflow
+}
+
+func (g *ULIDGenerator) random(ms uint64) (ULID, error) {
+	var u ULID
+	putUint48(u[:6], ms)
+	if _, err := g.Generator.Read(u[6:]); err != nil {
+		return ULID{}, err
+	}
+	return u, nil
+}
+
+// crockfordValue returns the value of a character in Crockford's base32, or -1.
+// Lower case letters are accepted and I, L and O are read as 1, 1 and 0.
+func crockfordValue(c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return 1
+	case 'O':
+		return 0
+	}
+	for i := 0; i < len(CharsetCrockford); i++ {
+		if CharsetCrockford[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,132 @@
+package goutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseULID(t *testing.T) {
+	u, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := decodeHex(t, "01563e3ab5d3d6764c61efb99302bd5b"); !bytes.Equal(u[:], want) {
+		t.Errorf("ParseULID() = %x, want %x", u[:], want)
+	}
+	if !u.Time().Equal(MillisToTime(1469922850259)) {
+		t.Errorf("Time() = %v, want %v", u.Time(), MillisToTime(1469922850259))
+	}
+	if u.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("String() = %s", u)
+	}
+	if lower, err := ParseULID("01arz3ndektsv4rrffq69g5fav"); err != nil || lower != u {
+		t.Errorf("ParseULID() of lower case = %s, %v", lower, err)
+	}
+
+	if max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ"); err != nil || max != (ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("ParseULID() of largest ULID = %x, %v", max[:], err)
+	}
+	for _, s := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "80000000000000000000000000"} {
+		if _, err := ParseULID(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ParseULID(%q) = %v, want ErrInvalidID", s, err)
+		}
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	u, err := NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Time().Before(before) || u.Time().After(time.Now()) {
+		t.Errorf("Time() = %v, want a time after %v", u.Time(), before)
+	}
+	if parsed, err := ParseULID(u.String()); err != nil || parsed != u {
+		t.Errorf("ParseULID(%s) = %s, %v", u, parsed, err)
+	}
+
+	a, err := NewULID(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewULID(WithRandSource(NewSeededSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a[6:], b[6:]) {
+		t.Errorf("ULIDs from the same seed differ: %s and %s", a, b)
+	}
+}
+
+func TestULIDGeneratorMonotonic(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := &ULIDGenerator{Monotonic: true, Now: func() time.Time { return now }}
+
+	prev, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if i == 50 {
+			// The clock going backwards must not break the order.
+			now = now.Add(-time.Second)
+		}
+		u, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.String() <= prev.String() {
+			t.Fatalf("ULID %s is not greater than %s", u, prev)
+		}
+		prev = u
+	}
+
+	now = now.Add(2 * time.Second)
+	u, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.Time().Equal(now) {
+		t.Errorf("Time() after the clock advanced = %v, want %v", u.Time(), now)
+	}
+}
+
+func TestULIDGeneratorOverflow(t *testing.T) {
+	source := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}
+	g := &ULIDGenerator{
+		Monotonic: true,
+		Generator: NewGenerator(bytes.NewReader(source)),
+		Now:       func() time.Time { return time.Unix(1700000000, 0) },
+	}
+	if _, err := g.New(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.New(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.New(); !errors.Is(err, ErrULIDOverflow) {
+		t.Errorf("New() = %v, want ErrULIDOverflow", err)
+	}
+}
+
+func TestULIDJSON(t *testing.T) {
+	u, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"01ARZ3NDEKTSV4RRFFQ69G5FAV"` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+	var decoded ULID
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != u {
+		t.Errorf("json.Unmarshal() = %s, %v", decoded, err)
+	}
+}
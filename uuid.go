@@ -0,0 +1,127 @@
+package goutil
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidID is returned when an identifier cannot be parsed.
+var ErrInvalidID = errors.New("invalid id")
+
+// UUID is a universally unique identifier as specified in RFC 9562.
+// It is marshalled as text and JSON in its canonical form.
+type UUID [16]byte
+
+// maxUUID is the Max UUID with all bits set, which like the Nil UUID has no variant and version.
+var maxUUID = UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// NewUUIDv4 returns a random version 4 UUID.
+// Options select another RandSource, e.g. a seeded one in tests.
+func NewUUIDv4(opts ...RandOption) (UUID, error) {
+	var u UUID
+	if _, err := applyRandOptions(defaultGenerator, opts).Read(u[:]); err != nil {
+		return UUID{}, err
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+// NewUUIDv7 returns a version 7 UUID, which starts with the current Unix time in milliseconds
+// followed by random bits, so UUIDs sort by creation time.
+// Options select another RandSource, e.g. a seeded one in tests.
+func NewUUIDv7(opts ...RandOption) (UUID, error) {
+	var u UUID
+	if _, err := applyRandOptions(defaultGenerator, opts).Read(u[6:]); err != nil {
+		return UUID{}, err
+	}
+	putUint48(u[:6], uint64(TimeToMillis(time.Now())))
+	u.setVersion(7)
+	return u, nil
+}
+
+// ParseUUID parses a UUID in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, ignoring case.
+// Apart from the Nil and Max UUIDs, the UUID must have the RFC 9562 variant and a version from 1 to 8.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return UUID{}, fmt.Errorf("%w: %q is not a UUID", ErrInvalidID, s)
+	}
+	hexDigits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(hexDigits)); err != nil {
+		return UUID{}, fmt.Errorf("%w: %q is not a UUID", ErrInvalidID, s)
+	}
+	if u == (UUID{}) || u == maxUUID {
+		return u, nil
+	}
+	if u[8]&0xc0 != 0x80 {
+		return UUID{}, fmt.Errorf("%w: %q does not have the RFC 9562 variant", ErrInvalidID, s)
+	}
+	if v := u.Version(); v < 1 || v > 8 {
+		return UUID{}, fmt.Errorf("%w: %q has unknown version %d", ErrInvalidID, s, v)
+	}
+	return u, nil
+}
+
+// String returns the UUID in its canonical lower case form.
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// Version returns the version of the UUID, e.g. 4 for random UUIDs.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the creation time of a version 7 UUID. For other versions it returns false.
+func (u UUID) Time() (time.Time, bool) {
+	if u.Version() != 7 {
+		return time.Time{}, false
+	}
+	return MillisToTime(int64(uint48(u[:6]))), true
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// setVersion sets the version and the RFC 9562 variant bits.
+func (u *UUID) setVersion(version byte) {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+}
+
+func putUint48(b []byte, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	copy(b, buf[2:])
+}
+
+func uint48(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[2:], b[:6])
+	return binary.BigEndian.Uint64(buf[:])
+}
@@ -0,0 +1,147 @@
+package goutil
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		new     func(...RandOption) (UUID, error)
+		version int
+	}{
+		{"v4", NewUUIDv4, 4},
+		{"v7", NewUUIDv7, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := tt.new()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := tt.new()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a == b {
+				t.Errorf("generated the same UUID twice: %s", a)
+			}
+			if a.Version() != tt.version {
+				t.Errorf("Version() = %d, want %d", a.Version(), tt.version)
+			}
+			if !uuidPattern.MatchString(a.String()) {
+				t.Errorf("String() = %s is not a canonical UUID", a)
+			}
+		})
+	}
+}
+
+func TestUUIDv7Time(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	u, err := NewUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := u.Time()
+	if !ok || got.Before(before) || got.After(time.Now()) {
+		t.Errorf("Time() = %v, %v, want a time after %v", got, ok, before)
+	}
+
+	v4, err := NewUUIDv4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v4.Time(); ok {
+		t.Error("Time() of a version 4 UUID succeeded")
+	}
+}
+
+// Example from RFC 9562, appendix A.6.
+func TestParseUUID(t *testing.T) {
+	u, err := ParseUUID("017F22E2-79B0-7CC3-98C4-DC0C0C07398F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "017f22e2-79b0-7cc3-98c4-dc0c0c07398f" {
+		t.Errorf("String() = %s", u)
+	}
+	if u.Version() != 7 {
+		t.Errorf("Version() = %d, want 7", u.Version())
+	}
+	if got, _ := u.Time(); !got.Equal(MillisToTime(1645557742000)) {
+		t.Errorf("Time() = %v, want %v", got, MillisToTime(1645557742000))
+	}
+
+	for _, s := range []string{
+		"",
+		"017f22e279b07cc398c4dc0c0c07398f",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398g",
+		"017f22e2+79b0-7cc3-98c4-dc0c0c07398f",
+		"{017f22e2-79b0-7cc3-98c4-dc0c0c07398f}",
+		"017f22e2-79b0-7cc3-18c4-dc0c0c07398f",
+		"017f22e2-79b0-7cc3-c8c4-dc0c0c07398f",
+		"017f22e2-79b0-0cc3-98c4-dc0c0c07398f",
+		"017f22e2-79b0-9cc3-98c4-dc0c0c07398f",
+	} {
+		if _, err := ParseUUID(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ParseUUID(%q) = %v, want ErrInvalidID", s, err)
+		}
+	}
+
+	for _, s := range []string{"00000000-0000-0000-0000-000000000000", "FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF"} {
+		if _, err := ParseUUID(s); err != nil {
+			t.Errorf("ParseUUID(%q) = %v", s, err)
+		}
+	}
+}
+
+func TestNewUUIDWithRandSource(t *testing.T) {
+	for _, new := range []func(...RandOption) (UUID, error){NewUUIDv4, NewUUIDv7} {
+		a, err := new(WithRandSource(NewSeededSource(42)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := new(WithRandSource(NewSeededSource(42)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(a[8:]) != string(b[8:]) {
+			t.Errorf("UUIDs from the same seed differ: %s and %s", a, b)
+		}
+		if _, err := ParseUUID(a.String()); err != nil {
+			t.Errorf("ParseUUID(%s) = %v", a, err)
+		}
+	}
+}
+
+func TestUUIDJSON(t *testing.T) {
+	u, err := ParseUUID("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]UUID{"id": u})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"id":"017f22e2-79b0-7cc3-98c4-dc0c0c07398f"}` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+
+	var decoded map[string]UUID
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["id"] != u {
+		t.Errorf("json.Unmarshal() = %s, want %s", decoded["id"], u)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"nope"}`), &decoded); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("json.Unmarshal() of invalid UUID = %v, want ErrInvalidID", err)
+	}
+}
@@ -0,0 +1,127 @@
+package goutil
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// XXH64, the 64 bit variant of xxHash, a fast non-cryptographic hash function
+// for checksums and hash tables, not for untrusted input.
+
+const (
+	xxh64BlockSize = 32
+	xxh64Size      = 8
+
+	xxh64Prime1 uint64 = 11400714785074694791
+	xxh64Prime2 uint64 = 14029467366897019727
+	xxh64Prime3 uint64 = 1609587929392839161
+	xxh64Prime4 uint64 = 9650029242287828579
+	xxh64Prime5 uint64 = 2870177450012600261
+)
+
+type xxh64 struct {
+	seed  uint64
+	v     [4]uint64
+	total uint64
+	buf   [xxh64BlockSize]byte
+	n     int
+}
+
+// newXXH64 returns an XXH64 hash with the given seed.
+func newXXH64(seed uint64) hash.Hash64 {
+	d := &xxh64{seed: seed}
+	d.Reset()
+	return d
+}
+
+func (d *xxh64) Size() int      { return xxh64Size }
+func (d *xxh64) BlockSize() int { return xxh64BlockSize }
+
+func (d *xxh64) Reset() {
+	d.v = [4]uint64{
+		d.seed + xxh64Prime1 + xxh64Prime2,
+		d.seed + xxh64Prime2,
+		d.seed,
+		d.seed - xxh64Prime1,
+	}
+	d.total = 0
+	d.n = 0
+}
+
+func (d *xxh64) Write(p []byte) (int, error) {
+	written := len(p)
+	d.total += uint64(written)
+	if d.n > 0 {
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+		if d.n < xxh64BlockSize {
+			return written, nil
+		}
+		d.blocks(d.buf[:])
+		d.n = 0
+	}
+	if len(p) >= xxh64BlockSize {
+		n := len(p) &^ (xxh64BlockSize - 1)
+		d.blocks(p[:n])
+		p = p[n:]
+	}
+	d.n = copy(d.buf[:], p)
+	return written, nil
+}
+
+func (d *xxh64) Sum(b []byte) []byte {
+	var sum [xxh64Size]byte
+	binary.BigEndian.PutUint64(sum[:], d.Sum64())
+	return append(b, sum[:]...)
+}
+
+func (d *xxh64) Sum64() uint64 {
+	var h uint64
+	if d.total >= xxh64BlockSize {
+		h = bits.RotateLeft64(d.v[0], 1) + bits.RotateLeft64(d.v[1], 7) +
+			bits.RotateLeft64(d.v[2], 12) + bits.RotateLeft64(d.v[3], 18)
+		for _, v := range d.v {
+			h = (h^xxh64Round(0, v))*xxh64Prime1 + xxh64Prime4
+		}
+	} else {
+		h = d.seed + xxh64Prime5
+	}
+	h += d.total
+
+	p := d.buf[:d.n]
+	for ; len(p) >= 8; p = p[8:] {
+		h ^= xxh64Round(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*xxh64Prime1 + xxh64Prime4
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * xxh64Prime1
+		h = bits.RotateLeft64(h, 23)*xxh64Prime2 + xxh64Prime3
+		p = p[4:]
+	}
+	for _, c := range p {
+		h ^= uint64(c) * xxh64Prime5
+		h = bits.RotateLeft64(h, 11) * xxh64Prime1
+	}
+
+	h ^= h >> 33
+	h *= xxh64Prime2
+	h ^= h >> 29
+	h *= xxh64Prime3
+	h ^= h >> 32
+	return h
+}
+
+// blocks processes whole 32 bytes stripes.
+func (d *xxh64) blocks(p []byte) {
+	for ; len(p) >= xxh64BlockSize; p = p[xxh64BlockSize:] {
+		for i := range d.v {
+			d.v[i] = xxh64Round(d.v[i], binary.LittleEndian.Uint64(p[8*i:]))
+		}
+	}
+}
+
+func xxh64Round(acc, input uint64) uint64 {
+	return bits.RotateLeft64(acc+input*xxh64Prime2, 31) * xxh64Prime1
+}
@@ -0,0 +1,64 @@
+package goutil
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestXXH64(t *testing.T) {
+	hundred := make([]byte, 100)
+	for i := range hundred {
+		hundred[i] = byte(i)
+	}
+	tests := []struct {
+		name  string
+		seed  uint64
+		input []byte
+		want  uint64
+	}{
+		{"empty", 0, nil, 0xef46db3751d8e999},
+		{"short", 0, []byte("abc"), 0x44bc2cf5ad770999},
+		{"one stripe", 0, []byte("Nobody inspects the spammish repetition"), 0xfbcea83c8a378bf1},
+		{"several stripes", 0, hundred, 0x6ac1e58032166597},
+		{"seeded", 1, []byte("abc"), 0xbea9ca8199328908},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newXXH64(tt.seed)
+			h.Write(tt.input)
+			if got := h.Sum64(); got != tt.want {
+				t.Errorf("Sum64() = %016x, want %016x", got, tt.want)
+			}
+			if got, want := fmt.Sprintf("%x", h.Sum(nil)), fmt.Sprintf("%016x", tt.want); got != want {
+				t.Errorf("Sum() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestXXH64Streaming(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 20)
+	whole := newXXH64(0)
+	whole.Write(data)
+	want := whole.Sum64()
+
+	for _, size := range []int{1, 3, 7, 31, 32, 33, 100} {
+		h := newXXH64(0)
+		for i := 0; i < len(data); i += size {
+			end := i + size
+			if end > len(data) {
+				end = len(data)
+			}
+			h.Write(data[i:end])
+		}
+		if got := h.Sum64(); got != want {
+			t.Errorf("writes of %d bytes: Sum64() = %016x, want %016x", size, got, want)
+		}
+	}
+
+	whole.Reset()
+	if got := whole.Sum64(); got != 0xef46db3751d8e999 {
+		t.Errorf("Sum64() after Reset = %016x", got)
+	}
+}